@@ -2,96 +2,326 @@ package publitio
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/big"
-	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Version is the current API version.
 const Version = "1.0.1"
 
+// DefaultUserAgent identifies this client and its version in requests.
+const DefaultUserAgent = "publitio-go/" + Version
+
+// DefaultBaseURL is the address of the Publitio API used when API.BaseURL is empty.
+const DefaultBaseURL = "https://api.publit.io/v1"
+
 // API is used to make all API calls.
 type API struct {
 	Key    string
 	Secret string
+
+	// HTTPClient is used for all requests, including file downloads, so timeouts, proxies
+	// and transports can be configured. If nil, a zero http.Client is used.
+	HTTPClient *http.Client
+
+	// BaseURL is the address of the Publitio API, for example a staging environment or a
+	// local test server. Endpoint paths are appended to it. If empty, DefaultBaseURL is used.
+	BaseURL string
+
+	// UserAgent, if not empty, replaces DefaultUserAgent as the User-Agent header of every request.
+	UserAgent string
+
+	// UserAgentSuffix, if not empty, is appended to the User-Agent header, separated by a space,
+	// to identify the application using the client, for example "my-app/2.3".
+	UserAgentSuffix string
+
+	// Defaults holds default query parameters per endpoint, keyed by the endpoint path
+	// without the leading slash, for example "files/create" or "files/list".
+	// Values passed to a call take precedence over the defaults.
+	Defaults map[string]url.Values
+
+	// ReadOnly makes every mutating call (uploads, PUT, DELETE, ...) fail with ErrReadOnly
+	// without contacting the server. Use it for dashboards and reports that should never
+	// modify anything.
+	ReadOnly bool
+
+	// Audit, if not nil, receives a record of every mutating call.
+	Audit AuditSink
+
+	// Policy, if not nil, is consulted before every call and can veto it by returning an error.
+	Policy PolicyFunc
+
+	// Normalize, if not nil, is applied to every query parameter value before it is sent,
+	// for example norm.NFC.String from golang.org/x/text/unicode/norm to send titles and
+	// descriptions in Unicode normalization form C.
+	Normalize func(string) string
+
+	// Clock, if not nil, provides the time used to sign requests instead of the system clock,
+	// for example to replay recorded requests against a fake server.
+	Clock func() time.Time
+
+	// CurlLog, if not nil, receives an equivalent curl command for every request, one per line,
+	// to help reproduce issues outside of Go. URLs are redacted unless CurlSecrets is set.
+	CurlLog     io.Writer
+	CurlSecrets bool
+
+	// DebugDump, if not nil, receives a dump of every request and response, with the URL
+	// redacted. Request bodies larger than 64 KiB, such as most uploads, are left out.
+	DebugDump io.Writer
+
+	// Progress, if not nil, is called as file uploads progress. It is called from the
+	// goroutine performing the upload, so it must be safe for concurrent use if uploads run
+	// concurrently.
+	Progress ProgressFunc
+
+	// Redact rewrites signed request URLs before they appear in errors and logs.
+	// If nil, RedactURL is used.
+	Redact Redactor
+
+	// FolderResolver, if not nil, caches folder path lookups made by EnsureFolderPath,
+	// uploads and listings using WithFolderPath. If nil, every lookup lists the folder tree.
+	FolderResolver *FolderResolver
+
+	// Events, if not nil, receives events about uploads, retries and rate limiting.
+	Events *EventBus
+
+	// UseNumber makes numbers in responses decode as json.Number instead of float64,
+	// so large file sizes, IDs and timestamps keep their exact value.
+	UseNumber bool
+
+	scopeTags []string // Tags added by Scoped, merged into per-call tags
+}
+
+// UserAgentString returns the User-Agent header sent with requests made through api.
+func (api *API) UserAgentString() string {
+	userAgent := api.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	if api.UserAgentSuffix != "" {
+		userAgent += " " + api.UserAgentSuffix
+	}
+	return userAgent
 }
 
+// PolicyFunc decides whether a call may be made. The path has no leading slash and values
+// are the per-call query parameters. A non-nil error vetoes the call; it is returned to the
+// caller wrapped, so it can be recovered with errors.Is or errors.As.
+type PolicyFunc func(method, path string, values url.Values) error
+
+// ErrReadOnly is returned by mutating calls made through a read-only API.
+var ErrReadOnly = errors.New("publitio: mutating call on a read-only API")
+
 // Response is the parsed JSON server response.
 type Response interface{}
 
+// RawResponse is a server response together with its HTTP metadata, as returned by CallRaw.
+type RawResponse struct {
+	StatusCode int         // HTTP status code
+	Header     http.Header // HTTP response headers
+	RequestID  string      // Value of the X-Request-Id header, if any, for support requests
+	Body       []byte      // Raw response body
+	Response   Response    // Parsed JSON, nil if the call failed
+}
+
+// status returns the HTTP status code of r, or 0 if there is no response.
+func (r *RawResponse) status() int {
+	if r == nil {
+		return 0
+	}
+	return r.StatusCode
+}
+
+// response returns the parsed JSON of r, or nil if there is no response.
+func (r *RawResponse) response() Response {
+	if r == nil {
+		return nil
+	}
+	return r.Response
+}
+
+// scopedPaths are the endpoints confined by Scoped.
+var scopedPaths = []string{"files/create", "files/list"}
+
+// Scoped returns a child client whose uploads and file listings are confined to the given folder
+// and tagged with the given tags. Tags passed to a call are added to the scope's tags rather
+// than replacing them. The child inherits the credentials and defaults of api, including the
+// tags of an enclosing scope; modifying the child does not affect api.
+func (api *API) Scoped(folder string, tags ...string) *API {
+	child := *api
+	child.Defaults = make(map[string]url.Values, len(api.Defaults)+2)
+	for path, values := range api.Defaults {
+		child.Defaults[path] = copyValues(values)
+	}
+	child.scopeTags = mergeTags(api.scopeTags, tags)
+
+	for _, path := range scopedPaths {
+		values := child.Defaults[path]
+		if values == nil {
+			values = make(url.Values)
+			child.Defaults[path] = values
+		}
+		values.Set("folder", folder)
+		if len(child.scopeTags) > 0 {
+			values.Set("tags", strings.Join(child.scopeTags, " "))
+		}
+	}
+
+	return &child
+}
+
+// mergeTags returns the tags of a followed by those of b that aren't in a.
+func mergeTags(a, b []string) []string {
+	merged := append([]string(nil), a...)
+	for _, tag := range b {
+		found := false
+		for _, t := range merged {
+			found = found || t == tag
+		}
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
 // UploadFile uploads a media file to the server using the filename.
+// The filename of an *os.File is sent along with the content; use NamedFile to name other readers.
 // To upload a file from memory, use api.UploadFile(fileReader, url.Values{"title": {"My file"}}).
 // To upload a file from a remote URL, use api.UploadFile(nil, url.Values{"file_url": {"https://example.com/file.png"}, "title": {"My file"}}).
-func (api *API) UploadFile(file io.Reader, values url.Values) (result Response, err error) {
-	url, err := api.publitioURL("/files/create", values)
-	if err != nil {
-		return nil, fmt.Errorf("error while creating Publitio url: %v", err)
+func (api *API) UploadFile(file io.Reader, values url.Values) (Response, error) {
+	return api.UploadFileContext(context.Background(), file, values)
+}
+
+// UploadFileContext is like UploadFile, but the upload is aborted when ctx is done.
+// Call options apply to the upload, except for retry policies.
+func (api *API) UploadFileContext(ctx context.Context, file io.Reader, values url.Values, opts ...CallOption) (Response, error) {
+	return api.uploadContext(ctx, "/files/create", file, values, opts)
+}
+
+// uploadContext posts file as a multipart form to the given path, with the checks,
+// auditing and call options of UploadFileContext.
+func (api *API) uploadContext(ctx context.Context, path string, file io.Reader, values url.Values, opts []CallOption) (result Response, err error) {
+	finishAudit := api.startAudit("POST", path)
+	defer func() { finishAudit(values, result, err) }()
+	defer finishProgress(file)
+
+	if api.ReadOnly {
+		return nil, ErrReadOnly
 	}
 
-	var res *http.Response
+	o := newCallOptions(opts)
+	ctx, cancel := o.context(ctx)
+	defer cancel()
 
-	if file == nil {
-		res, err = http.Post(url, "multipart/form-data", &bytes.Buffer{})
-	} else {
-		content, err := ioutil.ReadAll(file)
+	// The policy is consulted before folders are created, so a veto leaves the account alone
+	if err := api.checkPolicy("POST", path, values); err != nil {
+		return nil, err
+	}
+	if o.folderPath != "" {
+		folderID, err := api.EnsureFolderPath(ctx, o.folderPath)
 		if err != nil {
-			return nil, fmt.Errorf("error while reading file %v", err)
+			return nil, err
 		}
+		values = copyValues(values)
+		values.Set("folder", folderID)
+	}
 
-		requestBody := &bytes.Buffer{}
-		multipartWriter := multipart.NewWriter(requestBody)
-		w, err := multipartWriter.CreateFormFile("file", "new ")
-		if err != nil {
-			return nil, fmt.Errorf("error while creating multipart writer: %v", err)
-		}
+	api.publish(EventUploadStarted, "POST", path, 1, nil)
+	raw, err := api.upload(ctx, path, file, values, o)
+	if err != nil {
+		api.publish(EventUploadFailed, "POST", path, 1, err)
+		api.publishFailure("POST", path, 1, err)
+		return nil, &requestError{method: "POST", path: path, status: raw.status(), attempt: 1, err: err}
+	}
+	api.publish(EventUploadCompleted, "POST", path, 1, nil)
+	return raw.Response, nil
+}
 
-		_, err = w.Write(content)
-		if err != nil {
-			return nil, fmt.Errorf("error while writing multipart data: %v", err)
-		}
+// UploadFileFromPath opens the file at path, uploads it like UploadFile and closes it.
+// The file's name and a content type derived from its extension are sent along with it.
+func (api *API) UploadFileFromPath(path string, values url.Values) (Response, error) {
+	return api.UploadFileFromPathContext(context.Background(), path, values)
+}
+
+// UploadFileFromPathContext is like UploadFileFromPath, but the upload is aborted when ctx is done.
+func (api *API) UploadFileFromPathContext(ctx context.Context, path string, values url.Values, opts ...CallOption) (Response, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening file: %w", err)
+	}
+	defer file.Close()
+
+	return api.UploadFileContext(ctx, file, values, opts...)
+}
+
+func (api *API) upload(ctx context.Context, path string, file io.Reader, values url.Values, o callOptions) (*RawResponse, error) {
+	url, err := api.publitioURL(path, values)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating Publitio url: %v", err)
+	}
 
-		err = multipartWriter.Close()
+	var req *http.Request
+	var body *multipartBody
+
+	if file == nil {
+		api.logCurl("POST", url, "")
+		req, err = http.NewRequestWithContext(ctx, "POST", url, &bytes.Buffer{})
 		if err != nil {
-			return nil, fmt.Errorf("error while closing the multipart writer: %v", err)
+			return nil, fmt.Errorf("error while creating HTTP request: %v", api.redactError(err))
 		}
-
-		client := http.Client{}
-		res, err = client.Post(url, "multipart/form-data; boundary="+multipartWriter.Boundary(), requestBody)
+		req.Header.Set("Content-Type", "multipart/form-data")
+	} else {
+		body, err = newMultipartBody(file, "file", api.Progress)
 		if err != nil {
-			return nil, fmt.Errorf("error while performing HTTP request: %v", err)
+			return nil, err
 		}
-	}
+		api.logCurl("POST", url, body.fileName)
 
-	defer func() {
-		err = res.Body.Close()
+		req, err = http.NewRequestWithContext(ctx, "POST", url, body)
 		if err != nil {
-			result = nil
+			return nil, fmt.Errorf("error while creating HTTP request: %v", api.redactError(err))
 		}
-	}()
+		req.ContentLength = body.length
+		req.Header.Set("Content-Type", body.contentType)
+	}
 
-	result, err = parseResponse(res)
+	o.setHeaders(req)
+	res, err := api.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error while parsing the response: %v", err)
+		if body != nil && body.readErr != nil {
+			return nil, fmt.Errorf("error while reading file: %w", body.readErr)
+		}
+		return nil, fmt.Errorf("error while performing HTTP request: %w", api.redactError(err))
 	}
 
-	return result, nil
+	return parseResponse(res, api.UseNumber)
 }
 
 // Get performs a GET request to the server, for example when listing all files.
 func (api *API) Get(path string, values url.Values) (Response, error) {
-	res, err := api.Call("GET", path, values)
+	return api.GetContext(context.Background(), path, values)
+}
+
+// GetContext is like Get, but the request is aborted when ctx is done.
+func (api *API) GetContext(ctx context.Context, path string, values url.Values, opts ...CallOption) (Response, error) {
+	res, err := api.CallContext(ctx, "GET", path, values, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error while performing Publitio API GET: %v", err)
+		return nil, fmt.Errorf("error while performing Publitio API GET: %w", err)
 	}
 
 	return res, nil
@@ -99,9 +329,14 @@ func (api *API) Get(path string, values url.Values) (Response, error) {
 
 // Put performs a PUT request to the server, for example when updating files.
 func (api *API) Put(path string, values url.Values) (Response, error) {
-	res, err := api.Call("PUT", path, values)
+	return api.PutContext(context.Background(), path, values)
+}
+
+// PutContext is like Put, but the request is aborted when ctx is done.
+func (api *API) PutContext(ctx context.Context, path string, values url.Values, opts ...CallOption) (Response, error) {
+	res, err := api.CallContext(ctx, "PUT", path, values, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error while performing Publitio API PUT: %v", err)
+		return nil, fmt.Errorf("error while performing Publitio API PUT: %w", err)
 	}
 
 	return res, nil
@@ -109,9 +344,14 @@ func (api *API) Put(path string, values url.Values) (Response, error) {
 
 // Delete performs a DELETE request to the server, for example when deleting files.
 func (api *API) Delete(path string, values url.Values) (Response, error) {
-	res, err := api.Call("DELETE", path, values)
+	return api.DeleteContext(context.Background(), path, values)
+}
+
+// DeleteContext is like Delete, but the request is aborted when ctx is done.
+func (api *API) DeleteContext(ctx context.Context, path string, values url.Values, opts ...CallOption) (Response, error) {
+	res, err := api.CallContext(ctx, "DELETE", path, values, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error while performing Publitio API DELETE: %v", err)
+		return nil, fmt.Errorf("error while performing Publitio API DELETE: %w", err)
 	}
 
 	return res, nil
@@ -119,62 +359,186 @@ func (api *API) Delete(path string, values url.Values) (Response, error) {
 
 // Call performs any request to the server; use Get, Put and Delete for convenience.
 // If you need a post request, you should probably use Upload or UploadFile.
-func (api *API) Call(method, path string, values url.Values) (result Response, err error) {
+func (api *API) Call(method, path string, values url.Values) (Response, error) {
+	return api.CallContext(context.Background(), method, path, values)
+}
+
+// CallContext is like Call, but the request is aborted when ctx is done.
+func (api *API) CallContext(ctx context.Context, method, path string, values url.Values, opts ...CallOption) (Response, error) {
+	raw, err := api.CallRaw(ctx, method, path, values, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return raw.Response, nil
+}
+
+// CallRaw is like CallContext, but returns the HTTP metadata and raw body of the response
+// along with the parsed JSON. If the server responded but the call failed, for example with
+// an *Error, the response is returned together with the error.
+func (api *API) CallRaw(ctx context.Context, method, path string, values url.Values, opts ...CallOption) (raw *RawResponse, err error) {
+	finishAudit := api.startAudit(method, path)
+	defer func() { finishAudit(values, raw.response(), err) }()
+
+	if api.ReadOnly && method != "GET" && method != "HEAD" {
+		return nil, ErrReadOnly
+	}
+	if err := api.checkPolicy(method, path, values); err != nil {
+		return nil, err
+	}
+
+	o := newCallOptions(opts)
+	ctx, cancel := o.context(ctx)
+	defer cancel()
+
+	backoff := o.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		raw, err := api.call(ctx, method, path, values, o)
+		if err == nil {
+			return raw, nil
+		}
+		api.publishFailure(method, path, attempt, err)
+		var readErr *readError
+		if !errors.As(err, &readErr) || !idempotent(method) || attempt >= o.retry.MaxAttempts {
+			return raw, &requestError{method: method, path: path, status: raw.status(), attempt: attempt, err: err}
+		}
+
+		if backoff > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return raw, &requestError{method: method, path: path, status: raw.status(), attempt: attempt, err: err}
+			}
+			backoff *= 2
+		}
+		api.publish(EventRetry, method, path, attempt, err)
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+func (api *API) call(ctx context.Context, method, path string, values url.Values, o callOptions) (*RawResponse, error) {
 	url, err := api.publitioURL(path, values)
 	if err != nil {
 		return nil, fmt.Errorf("error while creating Publitio URL: %v", err)
 	}
-	req, err := http.NewRequest(method, url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error while creating HTTP request: %v", err)
+		return nil, fmt.Errorf("error while creating HTTP request: %v", api.redactError(err))
 	}
-	client := http.Client{}
-	res, err := client.Do(req)
+	api.logCurl(method, url, "")
+	o.setHeaders(req)
+	res, err := api.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error while performing HTTP request: %v", err)
+		return nil, fmt.Errorf("error while performing HTTP request: %w", api.redactError(err))
 	}
 
-	defer func() {
-		err = res.Body.Close()
-		if err != nil {
-			result = nil
-		}
-	}()
+	return parseResponse(res, api.UseNumber)
+}
 
-	result, err = parseResponse(res)
+func (api *API) checkPolicy(method, path string, values url.Values) error {
+	if api.Policy == nil {
+		return nil
+	}
+	err := api.Policy(method, strings.TrimPrefix(path, "/"), values)
 	if err != nil {
-		return nil, fmt.Errorf("error while parsing the Publitio response: %v", err)
+		return fmt.Errorf("%s %s rejected by policy: %w", method, strings.TrimPrefix(path, "/"), err)
 	}
+	return nil
+}
+
+// requestError adds the context of a failed request to the underlying error.
+// It never includes the query, which carries the credentials.
+type requestError struct {
+	method  string
+	path    string
+	status  int // HTTP status code, 0 if no response was received
+	attempt int
+	err     error
+}
+
+func (e *requestError) Error() string {
+	path := strings.TrimPrefix(e.path, "/")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if e.status == 0 {
+		return fmt.Sprintf("%s %s (attempt %d): %v", e.method, path, e.attempt, e.err)
+	}
+	return fmt.Sprintf("%s %s (attempt %d, HTTP status %d): %v", e.method, path, e.attempt, e.status, e.err)
+}
 
-	return result, nil
+func (e *requestError) Unwrap() error {
+	return e.err
 }
 
-func parseResponse(res *http.Response) (Response, error) {
+// readError reports that the response body could not be read completely.
+type readError struct {
+	n   int // Number of bytes read before the failure
+	err error
+}
+
+func (e *readError) Error() string {
+	return fmt.Sprintf("error while reading response after %d bytes: %v", e.n, e.err)
+}
+
+func (e *readError) Unwrap() error {
+	return e.err
+}
+
+// parseResponse reads and parses the response body. Responses with a non-2xx status or
+// "success": false in the payload are reported as *Error, *HTTPError or *FailureError.
+// The returned RawResponse is never nil; its Response is only set if there is no error.
+// If useNumber is set, numbers are parsed as json.Number.
+func parseResponse(res *http.Response, useNumber bool) (*RawResponse, error) {
 	defer res.Body.Close()
+	raw := &RawResponse{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		RequestID:  res.Header.Get("X-Request-Id"),
+	}
+
 	data, err := ioutil.ReadAll(res.Body)
+	raw.Body = data
 	if err != nil {
-		return nil, fmt.Errorf("error while reading response: %v", err)
+		return raw, &readError{n: len(data), err: err}
 	}
 
 	var r interface{}
-	err = json.Unmarshal(data, &r)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if useNumber {
+		decoder.UseNumber()
+	}
+	err = decoder.Decode(&r)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after the JSON value")
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error while parsing JSON %s: %v", data, err)
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return raw, &HTTPError{StatusCode: res.StatusCode, ContentType: res.Header.Get("Content-Type"), Body: data}
+		}
+		return raw, fmt.Errorf("error while parsing the Publitio response %s: %v", data, err)
 	}
 
-	return r, nil
+	if err := checkResponse(res.StatusCode, data, r); err != nil {
+		return raw, err
+	}
+	raw.Response = r
+	return raw, nil
 }
 
 func (api *API) publitioURL(path string, values url.Values) (string, error) {
-	const baseURL = "https://api.publit.io/v1"
-	var u *url.URL
-	var err error
-
-	if path[0] == '/' {
-		u, err = url.Parse(baseURL + path)
-	} else {
-		u, err = url.Parse(baseURL + "/" + path)
+	baseURL := api.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
 	}
+
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/"))
 	if err != nil {
 		return "", err
 	}
@@ -185,7 +549,11 @@ func (api *API) publitioURL(path string, values url.Values) (string, error) {
 	}
 
 	// Apparently this has to be a 32-bit number, but Unix() returns a 64-bit number
-	timestamp := strconv.FormatInt(time.Now().Unix()%0xFFFFFFFF, 10)
+	now := time.Now
+	if api.Clock != nil {
+		now = api.Clock
+	}
+	timestamp := strconv.FormatInt(now().Unix()%0xFFFFFFFF, 10)
 
 	queryValues := make(url.Values)
 	queryValues["api_nonce"] = []string{nonce}
@@ -193,7 +561,7 @@ func (api *API) publitioURL(path string, values url.Values) (string, error) {
 	queryValues["api_key"] = []string{api.Key}
 	queryValues["api_signature"] = []string{signature(api.Secret, timestamp, nonce)}
 	queryValues["api_kit"] = []string{"go-" + Version}
-	for k, v := range values {
+	for k, v := range api.params(path, values) {
 		queryValues[k] = v
 	}
 
@@ -201,6 +569,47 @@ func (api *API) publitioURL(path string, values url.Values) (string, error) {
 	return u.String(), nil
 }
 
+// params returns the query parameters sent with a call to path, other than the signing
+// parameters: the defaults of the endpoint, the per-call values and the scope's tags,
+// normalized by api.Normalize.
+func (api *API) params(path string, values url.Values) url.Values {
+	params := make(url.Values)
+	for k, v := range api.Defaults[strings.TrimPrefix(path, "/")] {
+		params[k] = v
+	}
+	for k, v := range values {
+		params[k] = v
+	}
+	if tags := values.Get("tags"); tags != "" && len(api.scopeTags) > 0 {
+		for _, scoped := range scopedPaths {
+			if strings.TrimPrefix(path, "/") == scoped {
+				params.Set("tags", strings.Join(mergeTags(api.scopeTags, strings.Fields(tags)), " "))
+			}
+		}
+	}
+	if api.Normalize != nil {
+		for k, v := range params {
+			if strings.HasPrefix(k, "api_") {
+				continue
+			}
+			normalized := make([]string, len(v))
+			for i := range v {
+				normalized[i] = api.Normalize(v[i])
+			}
+			params[k] = normalized
+		}
+	}
+	return params
+}
+
+func copyValues(values url.Values) url.Values {
+	c := make(url.Values, len(values))
+	for k, v := range values {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
 func signature(secret, timestamp, nonce string) string {
 	sum := sha1.Sum([]byte(timestamp + nonce + secret))
 	hexSum := make([]byte, hex.EncodedLen(len(sum)))
@@ -0,0 +1,661 @@
+package publitio
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// recordedRequest is a copy of a request received by a fakeServer.
+type recordedRequest struct {
+	Method        string
+	Path          string
+	Query         url.Values
+	Header        http.Header
+	ContentLength int64 // -1 for chunked requests
+	Body          []byte
+}
+
+const (
+	testKey    = "key"
+	testSecret = "secret"
+)
+
+// fakeServer records every API request it receives and answers with a canned JSON body.
+// Every API request must be signed with testKey and testSecret, otherwise the test fails.
+// Requests outside of /v1/ are served from media, like the delivery CDN.
+type fakeServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	requests  []recordedRequest
+	response  string                           // Default response body
+	responses map[string]string                // Response bodies by request path, e.g. "/v1/files/show/fileId"
+	media     map[string]string                // Unsigned content by request path, e.g. "/file/fileId.txt"
+	respond   func(req recordedRequest) string // If set, computes response bodies instead
+	truncate  int                              // Number of upcoming responses to cut off midway
+	status    int                              // HTTP status of responses, 200 if zero
+	mediaType string                           // Content-Type of responses, application/json if empty
+	header    http.Header                      // Extra headers of responses
+}
+
+// newFakeServer starts a fakeServer and routes all requests made through
+// http.DefaultTransport to it for the duration of the test.
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{t: t, response: `{"success": true}`}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	target, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		return transport.RoundTrip(req)
+	})
+
+	t.Cleanup(func() {
+		http.DefaultTransport = transport
+		s.Close()
+	})
+	return s
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		s.mu.Lock()
+		content, ok := s.media[r.URL.Path]
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+		return
+	}
+
+	if err := checkSignature(r.URL.Query()); err != nil {
+		s.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"success": false, "error": {"message": "Unauthorized", "code": 401}}`))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recordedRequest{
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		Query:         r.URL.Query(),
+		Header:        r.Header.Clone(),
+		ContentLength: r.ContentLength,
+		Body:          body,
+	})
+	response, ok := s.responses[r.URL.Path]
+	if !ok {
+		response = s.response
+	}
+	if s.respond != nil {
+		response = s.respond(s.requests[len(s.requests)-1])
+	}
+	truncate := s.truncate > 0
+	if truncate {
+		s.truncate--
+	}
+	status, mediaType := s.status, s.mediaType
+	for key, values := range s.header {
+		w.Header()[key] = values
+	}
+	s.mu.Unlock()
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+	w.Header().Set("Content-Type", mediaType)
+	if truncate {
+		// Promise the whole body but send only half of it, like a reset connection
+		w.Header().Set("Content-Length", strconv.Itoa(len(response)))
+		w.WriteHeader(status)
+		w.Write([]byte(response[:len(response)/2]))
+		return
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(response))
+}
+
+// lastRequest returns the most recent request, failing the test if there is none.
+func (s *fakeServer) lastRequest(t *testing.T) recordedRequest {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		t.Fatal("no request was made")
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// countRequests returns the number of requests made to the given path.
+func (s *fakeServer) countRequests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, req := range s.requests {
+		if req.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// checkSignature validates the signing parameters the way the Publitio server does,
+// independently of the package's own implementation.
+func checkSignature(query url.Values) error {
+	if query.Get("api_key") != testKey {
+		return fmt.Errorf("got api_key %q, want %q", query.Get("api_key"), testKey)
+	}
+	timestamp, nonce := query.Get("api_timestamp"), query.Get("api_nonce")
+	if timestamp == "" || nonce == "" {
+		return errors.New("missing api_timestamp or api_nonce")
+	}
+	if len(nonce) != 8 {
+		return fmt.Errorf("got api_nonce %q, want 8 digits", nonce)
+	}
+	want := fmt.Sprintf("%x", sha1.Sum([]byte(timestamp+nonce+testSecret)))
+	if got := query.Get("api_signature"); got != want {
+		return fmt.Errorf("got api_signature %q, want %q", got, want)
+	}
+	return nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestUploadFileMultipart(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	content := []byte("\x89PNG\r\n\x1a\nnot really a png")
+	_, err := api.UploadFile(bytes.NewReader(content), url.Values{"title": {"My file"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/files/create" {
+		t.Errorf("got %s %s, want POST /v1/files/create", req.Method, req.Path)
+	}
+	if got := req.Query.Get("title"); got != "My file" {
+		t.Errorf("got title %q, want %q", got, "My file")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != "multipart/form-data" || params["boundary"] == "" {
+		t.Fatalf("got Content-Type %q, want multipart/form-data with a boundary", req.Header.Get("Content-Type"))
+	}
+	if !bytes.HasSuffix(bytes.TrimSpace(req.Body), []byte("--"+params["boundary"]+"--")) {
+		t.Error("body is not terminated by the closing boundary")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"])
+	part, err := reader.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.FormName() != "file" {
+		t.Errorf("got form name %q, want %q", part.FormName(), "file")
+	}
+	if part.FileName() != "file" {
+		t.Errorf("got filename %q, want %q", part.FileName(), "file")
+	}
+	// The raw header pins the exact wire format, quoting included
+	if got, want := part.Header.Get("Content-Disposition"), `form-data; name="file"; filename="file"`; got != want {
+		t.Errorf("got Content-Disposition %q, want %q", got, want)
+	}
+	if got := part.Header.Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("got Content-Type %q, want %q", got, "application/octet-stream")
+	}
+	got, err := ioutil.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("got file content %q, want %q", got, content)
+	}
+
+	if _, err := reader.NextPart(); err == nil {
+		t.Error("got more than one part")
+	}
+}
+
+// onlyReader hides every method of the wrapped reader except Read, so its size is unknown.
+type onlyReader struct {
+	io.Reader
+}
+
+func TestUploadFileStreaming(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+	content := bytes.Repeat([]byte("0123456789"), 100000)
+
+	file, err := ioutil.TempFile("", "publitio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	file.Write(content)
+	file.Seek(10, io.SeekStart)
+
+	readers := map[string]io.Reader{
+		"bytes.Reader": bytes.NewReader(content[10:]),
+		"os.File":      file,
+		"unknown size": onlyReader{bytes.NewReader(content[10:])},
+	}
+	for name, reader := range readers {
+		if _, err := api.UploadFile(reader, nil); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		req := server.lastRequest(t)
+		if name == "unknown size" {
+			if req.ContentLength != -1 {
+				t.Errorf("%s: got Content-Length %d, want a chunked request", name, req.ContentLength)
+			}
+		} else if req.ContentLength != int64(len(req.Body)) {
+			t.Errorf("%s: got Content-Length %d, want %d", name, req.ContentLength, len(req.Body))
+		}
+
+		_, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		part, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).NextPart()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, _ := ioutil.ReadAll(part)
+		if !bytes.Equal(got, content[10:]) {
+			t.Errorf("%s: got %d bytes of content, want %d", name, len(got), len(content)-10)
+		}
+	}
+}
+
+func TestUploadFileName(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	dir, err := ioutil.TempDir("", "publitio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "holiday \"photo\".png")
+	if err := ioutil.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		file            io.Reader
+		wantName        string
+		wantContentType string
+	}{
+		{file, `holiday "photo".png`, "image/png"},
+		{NamedFile(strings.NewReader("content"), "cover.jpg", ""), "cover.jpg", "image/jpeg"},
+		{NamedFile(strings.NewReader("content"), "data", "text/plain"), "data", "text/plain"},
+		{NamedFile(strings.NewReader("content"), "", ""), defaultFileName, "application/octet-stream"},
+	}
+	for _, test := range tests {
+		if _, err := api.UploadFile(test.file, nil); err != nil {
+			t.Fatal(err)
+		}
+		req := server.lastRequest(t)
+		if req.ContentLength != int64(len(req.Body)) {
+			t.Errorf("%s: got Content-Length %d, want %d", test.wantName, req.ContentLength, len(req.Body))
+		}
+		_, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		part, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.FileName() != test.wantName {
+			t.Errorf("got filename %q, want %q", part.FileName(), test.wantName)
+		}
+		if got := part.Header.Get("Content-Type"); got != test.wantContentType {
+			t.Errorf("%s: got Content-Type %q, want %q", test.wantName, got, test.wantContentType)
+		}
+	}
+}
+
+func TestUploadFileReadError(t *testing.T) {
+	newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+	errDisk := errors.New("disk on fire")
+
+	reader := io.MultiReader(strings.NewReader("some content"), iotest.ErrReader(errDisk))
+	_, err := api.UploadFile(reader, nil)
+	if !errors.Is(err, errDisk) || !strings.Contains(err.Error(), "error while reading file") {
+		t.Errorf("got error %v, want a file read error", err)
+	}
+}
+
+func TestUploadFileFromURL(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	_, err := api.UploadFile(nil, url.Values{"file_url": {"https://example.org/file.png"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/files/create" {
+		t.Errorf("got %s %s, want POST /v1/files/create", req.Method, req.Path)
+	}
+	if got := req.Query.Get("file_url"); got != "https://example.org/file.png" {
+		t.Errorf("got file_url %q, want %q", got, "https://example.org/file.png")
+	}
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+		t.Errorf("got Content-Type %q, want multipart/form-data", req.Header.Get("Content-Type"))
+	}
+	if len(req.Body) != 0 {
+		t.Errorf("got body %q, want an empty body", req.Body)
+	}
+}
+
+func TestCallSignature(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	for _, method := range []string{"GET", "PUT", "DELETE"} {
+		if _, err := api.Call(method, "files/list", url.Values{"limit": {"1"}}); err != nil {
+			t.Fatal(err)
+		}
+		if req := server.lastRequest(t); req.Method != method || req.Path != "/v1/files/list" {
+			t.Errorf("got %s %s, want %s /v1/files/list", req.Method, req.Path, method)
+		}
+	}
+}
+
+func TestCheckSignatureRejectsWrongSecret(t *testing.T) {
+	api := API{Key: testKey, Secret: "wrong secret"}
+	u, err := api.publitioURL("files/list", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkSignature(parsed.Query()) == nil {
+		t.Error("a URL signed with the wrong secret was accepted")
+	}
+}
+
+func TestCallRetriesTruncatedResponse(t *testing.T) {
+	server := newFakeServer(t)
+	server.truncate = DefaultRetryPolicy.MaxAttempts - 1
+	api := API{Key: testKey, Secret: testSecret}
+
+	if _, err := api.Get("files/list", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.requests) != DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("got %d requests, want %d", len(server.requests), DefaultRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestCallTruncatedResponseError(t *testing.T) {
+	server := newFakeServer(t)
+	server.truncate = DefaultRetryPolicy.MaxAttempts
+	api := API{Key: testKey, Secret: testSecret}
+
+	_, err := api.Get("files/list", nil)
+	var readErr *readError
+	if !errors.As(err, &readErr) {
+		t.Fatalf("got error %v, want a read error", err)
+	}
+	if want := len(server.response) / 2; readErr.n != want {
+		t.Errorf("got %d bytes read, want %d", readErr.n, want)
+	}
+	if !strings.Contains(err.Error(), "after "+strconv.Itoa(readErr.n)+" bytes") {
+		t.Errorf("error %q does not mention the number of bytes read", err)
+	}
+}
+
+func TestUploadFileDoesNotRetry(t *testing.T) {
+	server := newFakeServer(t)
+	server.truncate = 1
+	api := API{Key: testKey, Secret: testSecret}
+
+	if _, err := api.UploadFile(strings.NewReader("content"), nil); err == nil {
+		t.Error("got no error for a truncated response")
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("got %d requests, want 1", len(server.requests))
+	}
+}
+
+func TestUnicodeValuesRoundTrip(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	titles := []string{
+		"Emoji 🎬🍿 & friends",
+		"日本語のタイトル",
+		"한국어 제목",
+		"עברית ومعها العربية",
+		"Plus+Percent%Ampersand&Equals=Hash#Question?",
+		"Combining e\u0301 and precomposed é",
+		"Tab\tand newline\n",
+	}
+	for _, title := range titles {
+		if _, err := api.Put("files/update/fileId", url.Values{"title": {title}, "description": {title}}); err != nil {
+			t.Fatal(err)
+		}
+		req := server.lastRequest(t)
+		if got := req.Query.Get("title"); got != title {
+			t.Errorf("got title %q, want %q", got, title)
+		}
+		if got := req.Query.Get("description"); got != title {
+			t.Errorf("got description %q, want %q", got, title)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+	api.Normalize = strings.NewReplacer("e\u0301", "é").Replace // A tiny stand-in for norm.NFC.String
+	api.Defaults = map[string]url.Values{"files/update/fileId": {"description": {"Cafe\u0301"}}}
+
+	if _, err := api.Put("files/update/fileId", url.Values{"title": {"Cafe\u0301"}}); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if got := req.Query.Get("title"); got != "Café" {
+		t.Errorf("got title %q, want %q", got, "Café")
+	}
+	if got := req.Query.Get("description"); got != "Café" {
+		t.Errorf("got description %q, want %q", got, "Café")
+	}
+}
+
+func TestClock(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+	api.Clock = func() time.Time { return time.Unix(1566727200, 0) }
+
+	if _, err := api.Get("files/list", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.lastRequest(t).Query.Get("api_timestamp"); got != "1566727200" {
+		t.Errorf("got api_timestamp %q, want %q", got, "1566727200")
+	}
+}
+
+func TestErrorContext(t *testing.T) {
+	server := newFakeServer(t)
+	server.truncate = DefaultRetryPolicy.MaxAttempts
+	api := API{Key: testKey, Secret: testSecret}
+
+	_, err := api.Get("/files/show/fileId", url.Values{"extra": {"1"}})
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	want := fmt.Sprintf("GET files/show/fileId (attempt %d, HTTP status 200): ", DefaultRetryPolicy.MaxAttempts)
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+	if strings.Contains(err.Error(), "extra") || strings.Contains(err.Error(), "api_") {
+		t.Errorf("error %q contains the query", err)
+	}
+}
+
+func TestContextCancelled(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := api.GetContext(ctx, "files/list", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if _, err := api.UploadFileContext(ctx, strings.NewReader("content"), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if len(server.requests) != 0 {
+		t.Errorf("got %d requests, want none", len(server.requests))
+	}
+}
+
+func TestUploadFileFromPath(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	dir, err := ioutil.TempDir("", "publitio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cover.jpg")
+	if err := ioutil.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := api.UploadFileFromPath(path, url.Values{"title": {"Cover"}}); err != nil {
+		t.Fatal(err)
+	}
+	req := server.lastRequest(t)
+	if !bytes.Contains(req.Body, []byte(`filename="cover.jpg"`)) || !bytes.Contains(req.Body, []byte("Content-Type: image/jpeg")) {
+		t.Errorf("got body %q, want the filename and content type of cover.jpg", req.Body)
+	}
+
+	if _, err := api.UploadFileFromPath(filepath.Join(dir, "missing.jpg"), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got error %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestCallRaw(t *testing.T) {
+	server := newFakeServer(t)
+	server.header = http.Header{"X-Request-Id": {"req-123"}}
+	api := API{Key: testKey, Secret: testSecret}
+
+	raw, err := api.CallRaw(context.Background(), "GET", "files/list", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.StatusCode != http.StatusOK || raw.RequestID != "req-123" || raw.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("got %+v, want status 200, the request ID and the headers", raw)
+	}
+	if string(raw.Body) != server.response || raw.Response == nil {
+		t.Errorf("got body %q and response %v, want both", raw.Body, raw.Response)
+	}
+
+	server.status = http.StatusNotFound
+	server.response = `{"success": false, "error": {"message": "File not found"}}`
+	raw, err = api.CallRaw(context.Background(), "GET", "files/show/missing", nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+	if raw == nil || raw.StatusCode != http.StatusNotFound || raw.RequestID != "req-123" || raw.Response != nil {
+		t.Errorf("got %+v, want the metadata of the failed response", raw)
+	}
+}
+
+func TestScoped(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret, Defaults: map[string]url.Values{"files/create": {"privacy": {"0"}}}}
+	teamA := api.Scoped("folderA", "teamA")
+
+	tests := []struct {
+		call       func() error
+		wantFolder string
+		wantTags   string
+	}{
+		{func() error { _, err := teamA.UploadFile(strings.NewReader("content"), nil); return err }, "folderA", "teamA"},
+		{func() error {
+			_, err := teamA.UploadFile(strings.NewReader("content"), url.Values{"tags": {"banner"}})
+			return err
+		}, "folderA", "teamA banner"},
+		{func() error { _, err := teamA.Get("files/list", url.Values{"tags": {"teamA video"}}); return err }, "folderA", "teamA video"},
+		{func() error { _, err := teamA.Scoped("folderB", "drafts").Get("files/list", nil); return err }, "folderB", "teamA drafts"},
+	}
+	for i, test := range tests {
+		if err := test.call(); err != nil {
+			t.Fatal(err)
+		}
+		req := server.lastRequest(t)
+		if req.Query.Get("folder") != test.wantFolder || req.Query.Get("tags") != test.wantTags {
+			t.Errorf("call %d: got folder %q and tags %q, want %q and %q", i, req.Query.Get("folder"), req.Query.Get("tags"), test.wantFolder, test.wantTags)
+		}
+	}
+	if req := server.lastRequest(t); req.Query.Get("privacy") != "" {
+		t.Errorf("got privacy %q on a listing, want the defaults per endpoint", req.Query.Get("privacy"))
+	}
+
+	if len(api.Defaults) != 1 || len(api.Defaults["files/create"]) != 1 || api.Defaults["files/create"].Get("privacy") != "0" {
+		t.Errorf("got parent defaults %v, want them unchanged", api.Defaults)
+	}
+	if _, err := api.Put("files/update/id", url.Values{"tags": {"banner"}}); err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Query.Get("tags") != "banner" || req.Query.Get("folder") != "" {
+		t.Errorf("got %v from the parent, want no scope", req.Query)
+	}
+}
@@ -0,0 +1,145 @@
+package publitio
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// archiveConcurrency is the number of files DownloadArchive fetches at the same time.
+const archiveConcurrency = 4
+
+// DownloadArchive streams the files with the given IDs into a zip archive written to w,
+// in the given order. Each entry is named after the file's public ID and extension.
+// Several files are fetched at the same time, but the archive is written sequentially,
+// so w doesn't need to support seeking. If any file fails or ctx is done, the archive is
+// left incomplete and an error naming the file is returned.
+func (api *API) DownloadArchive(ctx context.Context, fileIDs []string, w io.Writer) error {
+	downloads := make([]chan archiveEntry, len(fileIDs))
+	for i := range downloads {
+		downloads[i] = make(chan archiveEntry, 1)
+	}
+
+	// A slot is taken before a download starts and released once it has been written,
+	// which bounds the number of open connections.
+	slots := make(chan struct{}, archiveConcurrency)
+	done := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(1)
+	go func() {
+		defer started.Done()
+		for i, id := range fileIDs {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			started.Add(1)
+			go func(id string, result chan<- archiveEntry) {
+				defer started.Done()
+				name, body, err := api.openFile(ctx, id)
+				result <- archiveEntry{name: name, body: body, err: err}
+			}(id, downloads[i])
+		}
+	}()
+
+	// abort stops starting new downloads and closes the ones that won't be written.
+	abort := func(remaining []chan archiveEntry) {
+		close(done)
+		go func() {
+			started.Wait()
+			for _, c := range remaining {
+				select {
+				case entry := <-c:
+					if entry.body != nil {
+						entry.body.Close()
+					}
+				default:
+				}
+			}
+		}()
+	}
+
+	zipWriter := zip.NewWriter(w)
+	for i, id := range fileIDs {
+		entry := <-downloads[i]
+		if entry.err != nil {
+			abort(downloads[i+1:])
+			return fmt.Errorf("error while downloading file %s: %w", id, entry.err)
+		}
+
+		zipEntry, err := zipWriter.Create(entry.name)
+		if err == nil {
+			_, err = io.Copy(zipEntry, entry.body)
+		}
+		entry.body.Close()
+		<-slots
+		if err != nil {
+			abort(downloads[i+1:])
+			return fmt.Errorf("error while writing file %s to the archive: %v", id, err)
+		}
+	}
+	close(done)
+
+	err := zipWriter.Close()
+	if err != nil {
+		return fmt.Errorf("error while closing the archive: %v", err)
+	}
+	return nil
+}
+
+type archiveEntry struct {
+	name string
+	body io.ReadCloser
+	err  error
+}
+
+// openFile looks up a file and opens its download URL, returning the archive entry name
+// and the file content.
+func (api *API) openFile(ctx context.Context, id string) (string, io.ReadCloser, error) {
+	res, err := api.GetContext(ctx, "files/show/"+id, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	downloadURL := stringField(res, "url_download")
+	if downloadURL == "" {
+		downloadURL = stringField(res, "url_preview")
+	}
+	if downloadURL == "" {
+		return "", nil, fmt.Errorf("no download URL in response %v", res)
+	}
+
+	name := stringField(res, "public_id")
+	if name == "" {
+		name = id
+	}
+	if extension := stringField(res, "extension"); extension != "" {
+		name += "." + extension
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("error while creating HTTP request: %v", api.redactError(err))
+	}
+	api.logCurl("GET", downloadURL, "")
+	body, err := api.do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("error while performing HTTP request: %v", api.redactError(err))
+	}
+	if body.StatusCode != http.StatusOK {
+		body.Body.Close()
+		return "", nil, fmt.Errorf("unexpected HTTP status %s", body.Status)
+	}
+	return name, body.Body, nil
+}
+
+// stringField returns the string value of a top-level field in a JSON object response.
+func stringField(res Response, key string) string {
+	object, _ := res.(map[string]interface{})
+	value, _ := object[key].(string)
+	return value
+}
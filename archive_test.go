@@ -0,0 +1,102 @@
+package publitio
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDownloadArchive(t *testing.T) {
+	server := newFakeServer(t)
+	server.responses = make(map[string]string)
+	server.media = make(map[string]string)
+
+	var ids []string
+	for i := 0; i < 2*archiveConcurrency+1; i++ {
+		id := fmt.Sprintf("id%d", i)
+		ids = append(ids, id)
+		server.responses["/v1/files/show/"+id] = fmt.Sprintf(
+			`{"success": true, "id": %q, "public_id": "file-%d", "extension": "txt", "url_download": "https://media.publit.io/download/%s.txt"}`,
+			id, i, id)
+		server.media["/download/"+id+".txt"] = "content of " + id
+	}
+
+	api := API{Key: testKey, Secret: testSecret}
+	var archive bytes.Buffer
+	if err := api.DownloadArchive(context.Background(), ids, &archive); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reader.File) != len(ids) {
+		t.Fatalf("got %d entries, want %d", len(reader.File), len(ids))
+	}
+	for i, f := range reader.File {
+		if want := fmt.Sprintf("file-%d.txt", i); f.Name != want {
+			t.Errorf("got entry %q, want %q", f.Name, want)
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "content of " + ids[i]; string(content) != want {
+			t.Errorf("got content %q, want %q", content, want)
+		}
+	}
+}
+
+func TestDownloadArchiveMissingFile(t *testing.T) {
+	server := newFakeServer(t)
+	server.responses = map[string]string{
+		"/v1/files/show/ok":      `{"success": true, "public_id": "ok", "url_download": "https://media.publit.io/download/ok"}`,
+		"/v1/files/show/missing": `{"success": true, "public_id": "missing", "url_download": "https://media.publit.io/download/missing"}`,
+	}
+	server.media = map[string]string{"/download/ok": "ok"}
+
+	api := API{Key: testKey, Secret: testSecret}
+	err := api.DownloadArchive(context.Background(), []string{"ok", "missing", "ok"}, ioutil.Discard)
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("got error %v, want an error naming the missing file", err)
+	}
+}
+
+func TestDownloadArchiveRequests(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "public_id": "a", "url_download": "https://media.publit.io/download/a"}`
+	server.media = map[string]string{"/download/a": "a"}
+
+	var mu sync.Mutex
+	userAgents := make(map[string]string)
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		userAgents[req.URL.Path] = req.Header.Get("User-Agent")
+		mu.Unlock()
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	var curlLog bytes.Buffer
+	api := API{Key: testKey, Secret: testSecret, HTTPClient: client, UserAgentSuffix: "archiver/1.0", CurlLog: &curlLog}
+	if err := api.DownloadArchive(context.Background(), []string{"a"}, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := userAgents["/download/a"], DefaultUserAgent+" archiver/1.0"; got != want {
+		t.Errorf("got download User-Agent %q, want %q", got, want)
+	}
+	if !strings.Contains(curlLog.String(), "https://media.publit.io/download/a") {
+		t.Errorf("got curl log %q, want the download", curlLog.String())
+	}
+}
@@ -0,0 +1,64 @@
+package publitio
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AuditRecord describes a single mutating call made through an API.
+type AuditRecord struct {
+	Time   time.Time // When the call was started
+	KeyID  string    // Fingerprint of the API key that made the call, never the key itself
+	Method string    // HTTP method, e.g. "POST" for uploads
+	Path   string    // Endpoint path without the leading slash, e.g. "files/delete/fileId"
+	// Values are the query parameters sent, including defaults and the scope of a Scoped API,
+	// but not the signing parameters. The folder of an upload with WithFolderPath is included
+	// once it has been resolved.
+	Values url.Values
+	Result Response // Parsed server response, nil on failure
+	Err    error    // Error returned to the caller, if any
+}
+
+// AuditSink receives a record of every mutating call once it has completed.
+// Implementations must be safe for concurrent use if the API is shared between goroutines.
+type AuditSink interface {
+	Audit(record AuditRecord)
+}
+
+// AuditFunc adapts an ordinary function to the AuditSink interface.
+type AuditFunc func(record AuditRecord)
+
+// Audit calls f(record).
+func (f AuditFunc) Audit(record AuditRecord) {
+	f(record)
+}
+
+// startAudit starts the record of a call to path; the returned function completes it with
+// the per-call values, which may have changed in the meantime, and hands it to api.Audit.
+func (api *API) startAudit(method, path string) func(url.Values, Response, error) {
+	if api.Audit == nil || method == "GET" || method == "HEAD" {
+		return func(url.Values, Response, error) {}
+	}
+
+	record := AuditRecord{
+		Time:   time.Now(),
+		KeyID:  keyID(api.Key),
+		Method: method,
+		Path:   strings.TrimPrefix(path, "/"),
+	}
+	return func(values url.Values, result Response, err error) {
+		record.Values = copyValues(api.params(path, values))
+		record.Result = result
+		record.Err = err
+		api.Audit.Audit(record)
+	}
+}
+
+// keyID returns a short fingerprint of key, which tells keys apart in logs without revealing them.
+func keyID(key string) string {
+	sum := sha1.Sum([]byte("publitio-key-id:" + key))
+	return hex.EncodeToString(sum[:4])
+}
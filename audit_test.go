@@ -0,0 +1,67 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAuditRecord(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderResponder
+	var records []AuditRecord
+	parent := API{Key: testKey, Secret: testSecret, Audit: AuditFunc(func(record AuditRecord) {
+		records = append(records, record)
+	})}
+	parent.Defaults = map[string]url.Values{"files/create": {"privacy": {"0"}}}
+	api := parent.Scoped("f1", "teamA")
+
+	ctx := context.Background()
+	if _, err := api.UploadFileContext(ctx, strings.NewReader("content"), url.Values{"tags": {"banner"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.UploadFileContext(ctx, strings.NewReader("content"), nil, WithFolderPath("docs")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.Get("files/list", nil); err != nil {
+		t.Fatal(err)
+	}
+	api.ReadOnly = true
+	if _, err := api.Delete("files/delete/id", nil); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("got error %v, want ErrReadOnly", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3 without the listing", len(records))
+	}
+	upload := records[0]
+	if upload.Method != "POST" || upload.Path != "files/create" || upload.Err != nil || upload.Result == nil {
+		t.Errorf("got %+v, want the successful upload", upload)
+	}
+	want := url.Values{"privacy": {"0"}, "folder": {"f1"}, "tags": {"teamA banner"}}
+	if upload.Values.Encode() != want.Encode() {
+		t.Errorf("got values %v, want the parameters sent %v", upload.Values, want)
+	}
+	if got := records[1].Values.Get("folder"); got != "f3" {
+		t.Errorf("got folder %q, want the resolved folder f3", got)
+	}
+	if deletion := records[2]; deletion.Method != "DELETE" || !errors.Is(deletion.Err, ErrReadOnly) {
+		t.Errorf("got %+v, want the refused deletion", deletion)
+	}
+
+	for _, record := range records {
+		if record.KeyID == "" || record.KeyID != keyID(testKey) || strings.Contains(record.KeyID, testKey) {
+			t.Errorf("got key ID %q, want a fingerprint of the key", record.KeyID)
+		}
+		for key := range record.Values {
+			if strings.HasPrefix(key, "api_") {
+				t.Errorf("got signing parameter %s in %v", key, record.Values)
+			}
+		}
+	}
+	if keyID("other") == keyID(testKey) {
+		t.Error("different keys have the same key ID")
+	}
+}
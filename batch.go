@@ -0,0 +1,51 @@
+package publitio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PartialCompletion is returned by batch operations that stopped starting new items because
+// their context deadline was too close for another item to finish, so that the items in
+// flight could complete cleanly. The results of the completed items are returned with it.
+type PartialCompletion struct {
+	Done      int      // Number of items that were started and completed
+	Remaining []string // Items that were not started, to be processed later
+}
+
+func (p *PartialCompletion) Error() string {
+	return fmt.Sprintf("stopped before the deadline after %d items, %d remaining", p.Done, len(p.Remaining))
+}
+
+// costEstimate tracks how long the items of a batch take, to predict whether
+// another item will finish before a deadline. It is safe for concurrent use.
+type costEstimate struct {
+	mu    sync.Mutex
+	total time.Duration
+	n     int
+}
+
+func (c *costEstimate) add(d time.Duration) {
+	c.mu.Lock()
+	c.total += d
+	c.n++
+	c.mu.Unlock()
+}
+
+// fits reports whether an item started now is expected to finish before the deadline of
+// ctx, based on the average duration of the items so far. Without a deadline or before
+// any item has completed, every item fits.
+func (c *costEstimate) fits(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.n == 0 {
+		return true
+	}
+	return time.Until(deadline) > c.total/time.Duration(c.n)
+}
@@ -0,0 +1,94 @@
+package publitio
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CallOption tunes a single call. It is accepted by the context-aware methods of API.
+type CallOption func(o *callOptions)
+
+// RetryPolicy controls how often an idempotent call (GET, PUT, DELETE) is made when its
+// response is cut off, for example because the connection was reset. Uploads are never
+// retried, since they aren't idempotent.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts; values below 1 mean a single attempt
+	Backoff     time.Duration // Delay before the first retry, doubled for every following one
+}
+
+// DefaultRetryPolicy is used by calls without WithRetryPolicy or WithoutRetry.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3}
+
+type callOptions struct {
+	header  http.Header
+	timeout time.Duration
+	retry   RetryPolicy
+
+	folderPath string
+}
+
+func newCallOptions(opts []CallOption) callOptions {
+	o := callOptions{retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithHeader adds a header to the request.
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithCallTimeout limits the duration of the call, including retries. Unlike WithTimeout,
+// which applies to every request of an API, it applies to a single call.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for the call.
+func WithRetryPolicy(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = policy
+	}
+}
+
+// WithoutRetry makes the call a single attempt.
+func WithoutRetry() CallOption {
+	return WithRetryPolicy(RetryPolicy{MaxAttempts: 1})
+}
+
+// WithFolderPath uploads into the folder at the given slash-separated path, such as
+// "marketing/2025", instead of the folder given by ID in the "folder" value. Missing folders
+// are created, see API.EnsureFolderPath, once API.Policy has allowed the upload; the policy
+// sees the values without the folder ID. It applies to uploads and FileService.List.
+func WithFolderPath(folderPath string) CallOption {
+	return func(o *callOptions) {
+		o.folderPath = folderPath
+	}
+}
+
+// context applies the call timeout, if any, to ctx.
+func (o callOptions) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// setHeaders adds the call headers to req.
+func (o callOptions) setHeaders(req *http.Request) {
+	for key, values := range o.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithHeader(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+	ctx := context.Background()
+
+	api.GetContext(ctx, "files/list", nil, WithHeader("X-Request-Id", "abc"), WithHeader("X-Request-Id", "def"))
+	if got := server.lastRequest(t).Header["X-Request-Id"]; strings.Join(got, ",") != "abc,def" {
+		t.Errorf("got X-Request-Id %q, want abc and def", got)
+	}
+
+	api.UploadFileContext(ctx, strings.NewReader("content"), nil, WithHeader("X-Request-Id", "ghi"))
+	if got := server.lastRequest(t).Header.Get("X-Request-Id"); got != "ghi" {
+		t.Errorf("got X-Request-Id %q on upload, want ghi", got)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	defer func() { http.DefaultTransport = transport }()
+	api := API{Key: testKey, Secret: testSecret}
+
+	start := time.Now()
+	_, err := api.GetContext(context.Background(), "files/list", nil, WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call took %v despite the timeout", elapsed)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		opts         []CallOption
+		truncate     int
+		wantRequests int
+		wantErr      bool
+	}{
+		{nil, 2, 3, false},
+		{nil, 3, 3, true},
+		{[]CallOption{WithoutRetry()}, 1, 1, true},
+		{[]CallOption{WithRetryPolicy(RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond})}, 4, 5, false},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			server := newFakeServer(t)
+			server.truncate = test.truncate
+			api := API{Key: testKey, Secret: testSecret}
+
+			_, err := api.GetContext(context.Background(), "files/list", nil, test.opts...)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if len(server.requests) != test.wantRequests {
+				t.Errorf("got %d requests, want %d", len(server.requests), test.wantRequests)
+			}
+		})
+	}
+}
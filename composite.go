@@ -0,0 +1,45 @@
+package publitio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// UploadWithVersions uploads a file and then creates a version of it for each of the given
+// value sets (for example url.Values{"extension": {"jpg"}, "w": {"300"}} for a thumbnail),
+// all versions concurrently. Tags, title and other metadata go in values, as for UploadFile.
+//
+// If any version fails, the others are cancelled and the error is returned together with
+// the uploaded file, which is not deleted. Version responses are in the order of versions.
+func (api *API) UploadWithVersions(ctx context.Context, file io.Reader, values url.Values, versions ...url.Values) (Response, []Response, error) {
+	uploaded, err := api.UploadFileContext(ctx, file, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	id := stringField(uploaded, "id")
+	if id == "" {
+		return uploaded, nil, fmt.Errorf("no file ID in response %v", uploaded)
+	}
+
+	results := make([]Response, len(versions))
+	g, ctx := newGroup(ctx)
+	for i, version := range versions {
+		i, version := i, version
+		g.Go(func() error {
+			res, err := api.CallContext(ctx, "POST", "files/versions/create/"+id, version)
+			if err != nil {
+				return fmt.Errorf("error while creating version %d of file %s: %w", i, id, err)
+			}
+			results[i] = res
+			return nil
+		})
+	}
+
+	err = g.Wait()
+	if err != nil {
+		return uploaded, results, err
+	}
+	return uploaded, results, nil
+}
@@ -0,0 +1,63 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestUploadWithVersions(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		if req.Path == "/v1/files/create" {
+			return `{"success": true, "id": "fileId"}`
+		}
+		return `{"success": true, "id": "version-` + req.Query.Get("extension") + `"}`
+	}
+	api := API{Key: testKey, Secret: testSecret}
+
+	file, versions, err := api.UploadWithVersions(context.Background(), strings.NewReader("content"),
+		url.Values{"tags": {"banner"}},
+		url.Values{"extension": {"jpg"}, "w": {"300"}},
+		url.Values{"extension": {"webp"}, "w": {"300"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stringField(file, "id"); got != "fileId" {
+		t.Errorf("got file ID %q, want fileId", got)
+	}
+	if len(versions) != 2 || stringField(versions[0], "id") != "version-jpg" || stringField(versions[1], "id") != "version-webp" {
+		t.Errorf("got versions %v, want version-jpg and version-webp in order", versions)
+	}
+
+	var paths []string
+	for _, req := range server.requests {
+		paths = append(paths, req.Method+" "+req.Path)
+	}
+	sort.Strings(paths)
+	want := []string{"POST /v1/files/create", "POST /v1/files/versions/create/fileId", "POST /v1/files/versions/create/fileId"}
+	if strings.Join(paths, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got requests %q, want %q", paths, want)
+	}
+}
+
+func TestUploadWithVersionsFailure(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		if req.Path == "/v1/files/create" {
+			return `{"success": true, "id": "fileId"}`
+		}
+		return `not JSON`
+	}
+	api := API{Key: testKey, Secret: testSecret}
+
+	file, _, err := api.UploadWithVersions(context.Background(), strings.NewReader("content"), nil, url.Values{"extension": {"jpg"}})
+	if err == nil || !strings.Contains(err.Error(), "version 0 of file fileId") {
+		t.Errorf("got error %v, want an error naming the version", err)
+	}
+	if stringField(file, "id") != "fileId" {
+		t.Error("the uploaded file is not returned on failure")
+	}
+}
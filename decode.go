@@ -0,0 +1,71 @@
+package publitio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetInto is like Get, but stores the JSON response in the value pointed to by v,
+// as json.Unmarshal does, instead of returning it.
+func (api *API) GetInto(path string, values url.Values, v interface{}) error {
+	return api.CallInto(context.Background(), "GET", path, values, v)
+}
+
+// PutInto is like Put, but stores the JSON response in the value pointed to by v.
+func (api *API) PutInto(path string, values url.Values, v interface{}) error {
+	return api.CallInto(context.Background(), "PUT", path, values, v)
+}
+
+// DeleteInto is like Delete, but stores the JSON response in the value pointed to by v.
+func (api *API) DeleteInto(path string, values url.Values, v interface{}) error {
+	return api.CallInto(context.Background(), "DELETE", path, values, v)
+}
+
+// CallInto is like CallContext, but stores the JSON response in the value pointed to by v.
+// Fields of v missing from the response are left alone. The response body is decoded
+// directly, so integer fields of v get their exact value even without API.UseNumber.
+func (api *API) CallInto(ctx context.Context, method, path string, values url.Values, v interface{}, opts ...CallOption) error {
+	raw, err := api.CallRaw(ctx, method, path, values, opts...)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(raw.Body, v)
+	if err != nil {
+		return fmt.Errorf("error while decoding response %s: %v", raw.Body, err)
+	}
+	return nil
+}
+
+// Do makes a call like Call and returns the JSON response decoded into a T, for example
+//
+//	list, err := publitio.Do[struct{ Files []publitio.File }](api, "GET", "files/list", nil)
+func Do[T any](api *API, method, path string, values url.Values) (T, error) {
+	return DoContext[T](context.Background(), api, method, path, values)
+}
+
+// DoContext is like Do, but the request is aborted when ctx is done.
+func DoContext[T any](ctx context.Context, api *API, method, path string, values url.Values, opts ...CallOption) (T, error) {
+	var v T
+	err := api.CallInto(ctx, method, path, values, &v, opts...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// decodeResponse stores a parsed response in the value pointed to by v. Integers beyond
+// the precision of float64 are only exact if the response was parsed with API.UseNumber.
+func decodeResponse(res Response, v interface{}) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("error while decoding response: %v", err)
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("error while decoding response %s: %v", data, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package publitio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetInto(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "files_count": 2, "files": [{"id": "a", "size": 1}, {"id": "b", "size": 2}]}`
+	api := API{Key: testKey, Secret: testSecret}
+
+	var list struct {
+		Count int    `json:"files_count"`
+		Files []File `json:"files"`
+	}
+	err := api.GetInto("files/list", nil, &list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 2 || len(list.Files) != 2 || list.Files[1].ID != "b" || list.Files[1].Size != 2 {
+		t.Errorf("got %+v, want the decoded listing", list)
+	}
+}
+
+func TestPutAndDeleteInto(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = testFileJSON
+	api := API{Key: testKey, Secret: testSecret}
+
+	var file File
+	if err := api.PutInto("files/update/xbRdLbwS", nil, &file); err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "xbRdLbwS" || server.lastRequest(t).Method != "PUT" {
+		t.Errorf("got %+v from %s, want the file from a PUT", file, server.lastRequest(t).Method)
+	}
+
+	var result struct {
+		Message string `json:"message"`
+	}
+	if err := api.DeleteInto("files/delete/xbRdLbwS", nil, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Message != "File uploaded" || server.lastRequest(t).Method != "DELETE" {
+		t.Errorf("got %+v from %s, want the message from a DELETE", result, server.lastRequest(t).Method)
+	}
+}
+
+func TestCallIntoError(t *testing.T) {
+	server := newFakeServer(t)
+	server.status = http.StatusNotFound
+	server.response = `{"success": false, "error": {"message": "File not found"}}`
+	api := API{Key: testKey, Secret: testSecret}
+
+	file := File{ID: "unchanged"}
+	err := api.CallInto(context.Background(), "GET", "files/show/missing", nil, &file)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+	if file.ID != "unchanged" {
+		t.Errorf("got %+v, want the target left alone", file)
+	}
+}
+
+func TestDo(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = testFileJSON
+	api := API{Key: testKey, Secret: testSecret}
+
+	file, err := Do[File](&api, "GET", "files/show/xbRdLbwS", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "xbRdLbwS" || file.Size != 26940 {
+		t.Errorf("got %+v, want the decoded file", file)
+	}
+
+	server.response = `{"success": true, "files": [{"id": "a"}, {"id": "b"}]}`
+	list, err := DoContext[struct{ Files []File }](context.Background(), &api, "GET", "files/list", nil, WithoutRetry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Files) != 2 || list.Files[1].ID != "b" {
+		t.Errorf("got %+v, want two files", list)
+	}
+
+	server.status = http.StatusNotFound
+	file, err = Do[File](&api, "GET", "files/show/missing", nil)
+	if !errors.Is(err, ErrNotFound) || file.ID != "" {
+		t.Errorf("got %+v and error %v, want a zero file and ErrNotFound", file, err)
+	}
+}
+
+func TestUseNumber(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "id": "big", "size": 9007199254740993}`
+	ctx := context.Background()
+
+	api := NewAPI(testKey, testSecret, WithUseNumber())
+	res, err := api.Get("files/show/big", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := res.(map[string]interface{})["size"]; size != json.Number("9007199254740993") {
+		t.Errorf("got size %#v, want the exact json.Number", size)
+	}
+	file, err := api.Files().Show(ctx, "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Size != 9007199254740993 {
+		t.Errorf("got size %d, want 9007199254740993", file.Size)
+	}
+
+	api = NewAPI(testKey, testSecret)
+	res, err = api.Get("files/show/big", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.(map[string]interface{})["size"].(float64); !ok {
+		t.Errorf("got size %#v, want a float64 without UseNumber", res.(map[string]interface{})["size"])
+	}
+	var into File
+	if err := api.CallInto(ctx, "GET", "files/show/big", nil, &into); err != nil {
+		t.Fatal(err)
+	}
+	if into.Size != 9007199254740993 {
+		t.Errorf("got size %d from CallInto, want 9007199254740993", into.Size)
+	}
+}
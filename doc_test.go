@@ -1,8 +1,13 @@
 package publitio
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 )
 
 func Example() {
@@ -37,6 +42,11 @@ func ExampleAPI_UploadFile() {
 	api.UploadFile(nil, url.Values{"file_url": {"https://example.org"}, "public_id": {"xxGh332"}})
 }
 
+func ExampleAPI_UploadFileFromPath() {
+	api := API{Key: "xxx", Secret: "yyy"}
+	api.UploadFileFromPath("path/to/file.png", url.Values{"title": {"My file"}})
+}
+
 func ExampleAPI_Delete() {
 	api := API{Key: "xxx", Secret: "yyy"}
 	api.Delete("files/delete/fileId", url.Values{}) // Delete a file with ID fileID
@@ -54,3 +64,80 @@ func ExampleAPI_Get() {
 	api.Get("files/list", url.Values{})                // List all files, no query parameters
 	api.Get("/files/list", nil)                        // You can always pass nil if you have no query parameters
 }
+
+func ExampleAPI_Defaults() {
+	api := API{Key: "xxx", Secret: "yyy"}
+	api.Defaults = map[string]url.Values{
+		"files/create": {"privacy": {"0"}, "folder": {"folderId"}}, // Every upload is private and goes to folderId
+		"files/list":   {"limit": {"50"}},
+	}
+
+	api.Get("files/list", nil)                         // Lists at most 50 files
+	api.Get("files/list", url.Values{"limit": {"10"}}) // Per-call values take precedence
+}
+
+func ExampleAPI_Scoped() {
+	api := API{Key: "xxx", Secret: "yyy"}
+	marketing := api.Scoped("marketingFolderId", "marketing", "2019")
+
+	reader, _ := os.Open("path/to/file")
+	defer reader.Close()
+
+	marketing.UploadFile(reader, url.Values{"title": {"Banner"}}) // Uploaded to marketingFolderId, tagged "marketing 2019"
+	marketing.Get("files/list", nil)                              // Lists only files in marketingFolderId
+}
+
+func ExampleAuditFunc() {
+	api := API{Key: "xxx", Secret: "yyy"}
+	api.Audit = AuditFunc(func(record AuditRecord) {
+		log.Printf("%s %s %s %v (error: %v)", record.Time.Format(time.RFC3339), record.Method, record.Path, record.Values, record.Err)
+	})
+
+	api.Put("files/update/fileId", url.Values{"title": {"New title"}}) // Recorded
+	api.Get("files/list", nil)                                         // Not recorded, GET calls don't modify anything
+}
+
+func ExamplePolicyFunc() {
+	errForbidden := errors.New("forbidden")
+
+	api := API{Key: "xxx", Secret: "yyy"}
+	api.Policy = func(method, path string, values url.Values) error {
+		if method == "DELETE" && !strings.HasPrefix(path, "files/delete/") {
+			return errForbidden // Only files may be deleted, never folders or players
+		}
+		return nil
+	}
+
+	_, err := api.Delete("folders/delete/folderId", nil)
+	if errors.Is(err, errForbidden) {
+		log.Print("not allowed to delete folders")
+	}
+}
+
+func ExampleAPI_UploadFileContext() {
+	api := API{Key: "xxx", Secret: "yyy"}
+	reader, _ := os.Open("path/to/file")
+	defer reader.Close()
+
+	// Give up if the upload takes longer than ten minutes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	api.UploadFileContext(ctx, reader, url.Values{"title": {"My file"}})
+}
+
+func ExampleNewAPI() {
+	api := NewAPI("xxx", "yyy", WithTimeout(time.Minute), WithUserAgent("my-app/1.0"))
+	api.Get("files/list", nil)
+}
+
+func ExampleFailureError() {
+	api := API{Key: "xxx", Secret: "yyy"}
+
+	// Publitio may answer with HTTP 200 but report {"success": false, ...};
+	// the payload is still available through the error.
+	_, err := api.Put("files/update/fileId", url.Values{"privacy": {"2"}})
+	var failure *FailureError
+	if errors.As(err, &failure) {
+		log.Printf("update failed: %s (payload %v)", failure.Message, failure.Response)
+	}
+}
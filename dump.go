@@ -0,0 +1,90 @@
+package publitio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+// maxDumpBody is the largest request body written to API.DebugDump. Response bodies,
+// such as file downloads, are cut off after that many bytes.
+const maxDumpBody = 64 << 10
+
+// do sends req, dumping the request and the response to api.DebugDump if it is set.
+func (api *API) do(req *http.Request) (*http.Response, error) {
+	client := api.httpClient()
+	req.Header.Set("User-Agent", api.UserAgentString())
+	if api.DebugDump == nil {
+		return client.Do(req)
+	}
+
+	withBody := req.ContentLength >= 0 && req.ContentLength <= maxDumpBody
+	dump, err := httputil.DumpRequestOut(req, withBody)
+	if err != nil {
+		fmt.Fprintf(api.DebugDump, "error while dumping request: %v\n\n", api.redactError(err))
+	} else {
+		api.DebugDump.Write(api.sanitizeDump(req, dump))
+		if !withBody {
+			fmt.Fprintf(api.DebugDump, "[body of %d bytes omitted]\n", req.ContentLength)
+		}
+		fmt.Fprintln(api.DebugDump)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	api.dumpResponse(res)
+	return res, nil
+}
+
+// dumpResponse writes res to api.DebugDump, with at most maxDumpBody bytes of its body.
+// The bytes read for the dump are put back in front of the rest of the body.
+func (api *API) dumpResponse(res *http.Response) {
+	dump, err := httputil.DumpResponse(res, false)
+	if err != nil {
+		fmt.Fprintf(api.DebugDump, "error while dumping response: %v\n\n", err)
+		return
+	}
+	api.DebugDump.Write(dump)
+
+	head, err := ioutil.ReadAll(io.LimitReader(res.Body, maxDumpBody+1))
+	res.Body = readCloser{io.MultiReader(bytes.NewReader(head), res.Body), res.Body}
+	if len(head) > maxDumpBody {
+		api.DebugDump.Write(head[:maxDumpBody])
+		fmt.Fprintf(api.DebugDump, "\n[rest of body omitted]\n")
+	} else {
+		api.DebugDump.Write(head)
+	}
+	if err != nil {
+		fmt.Fprintf(api.DebugDump, "\nerror while dumping response body: %v\n", err)
+	}
+	fmt.Fprintln(api.DebugDump)
+}
+
+// readCloser reads from Reader and closes Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// defaultHTTPClient is shared by all APIs without an HTTPClient. It uses
+// http.DefaultTransport, so connections are pooled across them.
+var defaultHTTPClient = &http.Client{}
+
+func (api *API) httpClient() *http.Client {
+	if api.HTTPClient != nil {
+		return api.HTTPClient
+	}
+	return defaultHTTPClient
+}
+
+// sanitizeDump replaces the signed request URI in a request dump with the redacted URL.
+func (api *API) sanitizeDump(req *http.Request, dump []byte) []byte {
+	return []byte(strings.Replace(string(dump), req.URL.RequestURI(), api.redact(req.URL.String()), 1))
+}
@@ -0,0 +1,78 @@
+package publitio
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDebugDump(t *testing.T) {
+	newFakeServer(t)
+	var dump bytes.Buffer
+	api := API{Key: testKey, Secret: testSecret, DebugDump: &dump}
+
+	api.Put("files/update/fileId", url.Values{"title": {"New title"}})
+	api.UploadFile(strings.NewReader("small file"), nil)
+	api.UploadFile(bytes.NewReader(make([]byte, maxDumpBody+1)), nil)
+
+	out := dump.String()
+	for _, want := range []string{
+		"PUT https://api.publit.io/v1/files/update/fileId?",
+		"POST https://api.publit.io/v1/files/create?",
+		`name="file"`,
+		"small file",
+		"[body of ",
+		"HTTP/1.1 200 OK",
+		`{"success": true}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dump does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "api_key="+testKey) {
+		t.Errorf("dump leaks credentials:\n%s", out)
+	}
+	if strings.Count(out, "api_signature=REDACTED") != 3 {
+		t.Errorf("got %d redacted signatures, want 3:\n%s", strings.Count(out, "api_signature=REDACTED"), out)
+	}
+}
+
+func TestDebugDumpLargeResponse(t *testing.T) {
+	server := newFakeServer(t)
+	content := strings.Repeat("x", 2*maxDumpBody)
+	server.responses = map[string]string{
+		"/v1/files/show/fileId": `{"success": true, "public_id": "file", "url_download": "https://media.publit.io/download/file"}`,
+	}
+	server.media = map[string]string{"/download/file": content}
+	var dump bytes.Buffer
+	api := API{Key: testKey, Secret: testSecret, DebugDump: &dump}
+
+	var archive bytes.Buffer
+	if err := api.DownloadArchive(context.Background(), []string{"fileId"}, &archive); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reader.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := ioutil.ReadAll(r)
+	if string(got) != content {
+		t.Errorf("got %d bytes of content, want %d", len(got), len(content))
+	}
+
+	out := dump.String()
+	if !strings.Contains(out, "[rest of body omitted]") || strings.Contains(out, strings.Repeat("x", maxDumpBody+1)) {
+		t.Errorf("download body was not cut off in the dump of %d bytes", len(out))
+	}
+	if !strings.Contains(out, `"public_id": "file"`) {
+		t.Errorf("dump does not contain the API response:\n%s", out)
+	}
+}
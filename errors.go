@@ -0,0 +1,213 @@
+package publitio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Common failures, which errors.Is matches against the errors returned for the
+// corresponding HTTP statuses or Publitio error codes.
+var (
+	ErrUnauthorized  = errors.New("publitio: unauthorized")   // 401 and 403
+	ErrQuotaExceeded = errors.New("publitio: quota exceeded") // 402
+	ErrNotFound      = errors.New("publitio: not found")      // 404
+	ErrRateLimited   = errors.New("publitio: rate limited")   // 429
+)
+
+// sentinel returns the common failure for the given status or error code, or nil.
+func sentinel(code int) error {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusPaymentRequired:
+		return ErrQuotaExceeded
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// transient reports whether err is a failure that may not recur, so the call is worth
+// retrying: a cut-off response, a network failure, rate limiting or a server error.
+// Vetoes, read-only APIs, client errors and cancellation are permanent.
+func transient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var readErr *readError
+	var netErr net.Error
+	var apiErr *Error
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &readErr), errors.As(err, &netErr), errors.Is(err, ErrRateLimited):
+		return true
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 500
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode >= 500
+	}
+	return false
+}
+
+// uploadRetryable reports whether a failed upload is safe to retry, because the server
+// provably didn't store the file: the connection couldn't be established, the call was
+// rate limited, or the server answered with an API error. Uploads aren't idempotent, so
+// cut-off responses and other network failures are not retried, since the file may have
+// been stored anyway.
+func uploadRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var apiErr *Error
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "dial", errors.As(err, &dnsErr), errors.Is(err, ErrRateLimited):
+		return true
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 500
+	}
+	return false
+}
+
+// Error is returned when Publitio reports a failure, either with a non-2xx HTTP status and
+// a JSON error payload, or as a *FailureError, which unwraps to an *Error.
+type Error struct {
+	StatusCode int      // HTTP status code of the response
+	Code       int      // Publitio error code from the payload, the HTTP status code if there is none
+	Message    string   // Error message from the payload, empty if there is none
+	Body       []byte   // Raw response body
+	Response   Response // The parsed payload
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("publitio API error %d", e.Code)
+	}
+	return fmt.Sprintf("publitio API error %d: %s", e.Code, e.Message)
+}
+
+// Is reports whether target is the common failure for the status or error code of e.
+func (e *Error) Is(target error) bool {
+	return target != nil && (target == sentinel(e.StatusCode) || target == sentinel(e.Code))
+}
+
+// HTTPError is returned when the server answers with a non-2xx HTTP status and a body
+// that is not JSON, for example an empty body or an HTML page from a proxy or load balancer.
+type HTTPError struct {
+	StatusCode  int    // HTTP status code of the response
+	ContentType string // Content-Type header of the response
+	Body        []byte // Raw response body, possibly empty
+}
+
+func (e *HTTPError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("unexpected HTTP status %d with an empty body", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected HTTP status %d with a non-JSON body (%s)", e.StatusCode, e.ContentType)
+}
+
+// Is reports whether target is the common failure for the status code of e.
+func (e *HTTPError) Is(target error) bool {
+	return target != nil && target == sentinel(e.StatusCode)
+}
+
+// FailureError is returned when Publitio answers with a 2xx HTTP status
+// but reports "success": false in the payload. Its fields are those of Error.
+type FailureError struct {
+	StatusCode int      // HTTP status code of the response
+	Code       int      // Publitio error code from the payload, the HTTP status code if there is none
+	Message    string   // Error message from the payload, empty if there is none
+	Body       []byte   // Raw response body
+	Response   Response // The parsed payload
+}
+
+func (e *FailureError) Error() string {
+	if e.Message == "" {
+		return "publitio reported failure"
+	}
+	return fmt.Sprintf("publitio reported failure: %s", e.Message)
+}
+
+// Unwrap returns the failure as an *Error, so errors.As finds it like any other API error.
+func (e *FailureError) Unwrap() error {
+	return (*Error)(e)
+}
+
+// checkResponse converts a response parsed from a request that returned the given
+// HTTP status code into an error, if the status or the payload report a failure.
+func checkResponse(status int, body []byte, r Response) error {
+	if status < 200 || status > 299 {
+		return newError(status, body, r)
+	}
+	m, ok := r.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if success, ok := m["success"].(bool); ok && !success {
+		return (*FailureError)(newError(status, body, r))
+	}
+	return nil
+}
+
+func newError(status int, body []byte, r Response) *Error {
+	code := errorCode(r)
+	if code == 0 {
+		code = status
+	}
+	return &Error{StatusCode: status, Code: code, Message: errorMessage(r), Body: body, Response: r}
+}
+
+// errorCode extracts the error code from an error payload, which is either
+// {"error": {"code": 404}} or {"code": 404}, or returns 0 if there is none.
+func errorCode(r Response) int {
+	m, ok := r.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	if e, ok := m["error"].(map[string]interface{}); ok {
+		if code, ok := intValue(e["code"]); ok {
+			return code
+		}
+	}
+	code, _ := intValue(m["code"])
+	return code
+}
+
+// intValue converts a number parsed from JSON, either a float64 or a json.Number, to an int.
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+// errorMessage extracts the error message from an error payload, which is either
+// {"error": {"message": "..."}}, {"error": "..."} or {"message": "..."}.
+func errorMessage(r Response) string {
+	m, ok := r.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch e := m["error"].(type) {
+	case string:
+		return strings.TrimSpace(e)
+	case map[string]interface{}:
+		if message, ok := e["message"].(string); ok {
+			return strings.TrimSpace(message)
+		}
+	}
+	message, _ := m["message"].(string)
+	return strings.TrimSpace(message)
+}
@@ -0,0 +1,172 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestErrorJSONPayload(t *testing.T) {
+	server := newFakeServer(t)
+	server.status = http.StatusNotFound
+	server.response = `{"success": false, "error": {"message": "File not found", "code": 40401}}`
+	api := API{Key: testKey, Secret: testSecret}
+
+	res, err := api.Get("files/show/fileId", nil)
+	if res != nil {
+		t.Errorf("got response %v, want nil", res)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error %v, want an *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "File not found" || apiErr.Response == nil {
+		t.Errorf("got %+v, want status 404, the message and the payload", apiErr)
+	}
+	if apiErr.Code != 40401 || string(apiErr.Body) != server.response {
+		t.Errorf("got code %d and body %q, want the code and body of the payload", apiErr.Code, apiErr.Body)
+	}
+	if !strings.Contains(err.Error(), "File not found") {
+		t.Errorf("error %q does not contain the message", err)
+	}
+}
+
+func TestErrorNonJSONBody(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		status    int
+		mediaType string
+		body      string
+	}{
+		{"empty", http.StatusBadGateway, "text/plain", ""},
+		{"html", http.StatusServiceUnavailable, "text/html", "<html><body>Service Unavailable</body></html>"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t)
+			server.status = test.status
+			server.mediaType = test.mediaType
+			server.response = test.body
+			api := API{Key: testKey, Secret: testSecret}
+
+			_, err := api.Get("files/list", nil)
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("got error %v, want an *HTTPError", err)
+			}
+			if httpErr.StatusCode != test.status || httpErr.ContentType != test.mediaType || string(httpErr.Body) != test.body {
+				t.Errorf("got %+v, want status %d, %s and the body", httpErr, test.status, test.mediaType)
+			}
+			var apiErr *Error
+			if errors.As(err, &apiErr) {
+				t.Errorf("got an *Error for a non-JSON body")
+			}
+		})
+	}
+}
+
+func TestErrorSuccessFalse(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": false, "message": "Invalid parameters"}`
+	api := API{Key: testKey, Secret: testSecret}
+
+	_, err := api.UploadFile(nil, nil)
+	var failure *FailureError
+	if !errors.As(err, &failure) {
+		t.Fatalf("got error %v, want a *FailureError", err)
+	}
+	if failure.StatusCode != http.StatusOK || failure.Message != "Invalid parameters" || failure.Response == nil {
+		t.Errorf("got %+v, want status 200, the message and the payload", failure)
+	}
+	if failure.Code != http.StatusOK {
+		t.Errorf("got code %d, want the HTTP status for a payload without a code", failure.Code)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Message != "Invalid parameters" {
+		t.Errorf("got error %v, want the failure as an *Error", err)
+	}
+}
+
+func TestErrorSentinels(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"not found", http.StatusNotFound, `{"success": false, "error": {"message": "File not found"}}`, ErrNotFound},
+		{"unauthorized", http.StatusUnauthorized, `{"success": false}`, ErrUnauthorized},
+		{"forbidden", http.StatusForbidden, ``, ErrUnauthorized},
+		{"rate limited", http.StatusTooManyRequests, `<html>Too Many Requests</html>`, ErrRateLimited},
+		{"quota exceeded", http.StatusOK, `{"success": false, "code": 402, "message": "Storage limit reached"}`, ErrQuotaExceeded},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t)
+			server.status = test.status
+			server.response = test.body
+			api := API{Key: testKey, Secret: testSecret}
+
+			_, err := api.Get("files/show/fileId", nil)
+			if !errors.Is(err, test.want) {
+				t.Errorf("got error %v, want %v", err, test.want)
+			}
+			for _, other := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrQuotaExceeded} {
+				if other != test.want && errors.Is(err, other) {
+					t.Errorf("error %v also matches %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&readError{err: io.ErrUnexpectedEOF}, true},
+		{&url.Error{Op: "Post", URL: "https://api.publit.io", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{&Error{StatusCode: http.StatusServiceUnavailable}, true},
+		{&HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{&Error{StatusCode: http.StatusTooManyRequests}, true},
+		{&Error{StatusCode: http.StatusBadRequest}, false},
+		{&HTTPError{StatusCode: http.StatusNotFound}, false},
+		{&FailureError{StatusCode: http.StatusOK}, false},
+		{ErrReadOnly, false},
+		{fmt.Errorf("POST files/create rejected by policy: %w", errors.New("denied")), false},
+		{fmt.Errorf("error while performing HTTP request: %w", context.Canceled), false},
+	}
+	for _, test := range tests {
+		if got := transient(test.err); got != test.want {
+			t.Errorf("transient(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestUploadRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&url.Error{Op: "Post", URL: "https://api.publit.io", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{&url.Error{Op: "Post", URL: "https://api.publit.io", Err: &net.DNSError{Err: "no such host", Name: "api.publit.io"}}, true},
+		{&Error{StatusCode: http.StatusTooManyRequests}, true},
+		{&Error{StatusCode: http.StatusServiceUnavailable}, true},
+		{&readError{err: io.ErrUnexpectedEOF}, false},
+		{&url.Error{Op: "Post", URL: "https://api.publit.io", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}, false},
+		{&HTTPError{StatusCode: http.StatusBadGateway}, false},
+		{&Error{StatusCode: http.StatusBadRequest}, false},
+		{ErrReadOnly, false},
+		{fmt.Errorf("error while performing HTTP request: %w", context.Canceled), false},
+	}
+	for _, test := range tests {
+		if got := uploadRetryable(test.err); got != test.want {
+			t.Errorf("uploadRetryable(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
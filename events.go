@@ -0,0 +1,103 @@
+package publitio
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType identifies what an Event reports.
+type EventType string
+
+// Types of events published on an EventBus.
+const (
+	EventUploadStarted   EventType = "upload_started"   // A multipart upload is about to be sent
+	EventUploadCompleted EventType = "upload_completed" // An upload succeeded
+	EventUploadFailed    EventType = "upload_failed"    // An upload failed, see Event.Err
+	EventRetry           EventType = "retry"            // A call is about to be retried after Event.Err
+	EventRateLimited     EventType = "rate_limited"     // A call failed with ErrRateLimited
+)
+
+// Event describes something that happened while making calls through an API.
+type Event struct {
+	Type    EventType
+	Time    time.Time
+	Method  string // HTTP method of the call
+	Path    string // Endpoint path without the leading slash, e.g. "files/create"
+	Attempt int    // Number of the attempt the event is about, starting at 1
+	Err     error  // The error of the attempt, for failures, retries and rate limiting
+}
+
+// EventBus delivers events to subscribers. Set it as API.Events to receive the events
+// of all calls made through that API and the children returned by Scoped.
+// The zero value is ready to use. It is safe for concurrent use.
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[int]func(Event)
+	next        int
+}
+
+// NewEventBus returns an EventBus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]func(Event))}
+}
+
+// Subscribe calls f with every event published from now on, until the returned function is
+// called. Events are delivered synchronously from the goroutine making the call, so f must
+// return quickly and be safe for concurrent use if calls are made concurrently.
+func (b *EventBus) Subscribe(f func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[int]func(Event))
+	}
+	id := b.next
+	b.next++
+	b.subscribers[id] = f
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Publish delivers event to all current subscribers.
+func (b *EventBus) Publish(event Event) {
+	b.mu.Lock()
+	subscribers := make([]func(Event), 0, len(b.subscribers))
+	for _, f := range b.subscribers {
+		subscribers = append(subscribers, f)
+	}
+	b.mu.Unlock()
+
+	for _, f := range subscribers {
+		f(event)
+	}
+}
+
+// publish publishes an event on api.Events, if set.
+func (api *API) publish(eventType EventType, method, path string, attempt int, err error) {
+	if api.Events == nil {
+		return
+	}
+	api.Events.Publish(Event{
+		Type:    eventType,
+		Time:    time.Now(),
+		Method:  method,
+		Path:    strings.TrimPrefix(path, "/"),
+		Attempt: attempt,
+		Err:     err,
+	})
+}
+
+// publishFailure publishes EventRateLimited if err reports rate limiting.
+func (api *API) publishFailure(method, path string, attempt int, err error) {
+	if errors.Is(err, ErrRateLimited) {
+		api.publish(EventRateLimited, method, path, attempt, err)
+	}
+}
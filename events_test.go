@@ -0,0 +1,104 @@
+package publitio
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordEvents subscribes to bus and returns a function listing the types of the events so far.
+func recordEvents(bus *EventBus) func() []EventType {
+	var mu sync.Mutex
+	var types []EventType
+	bus.Subscribe(func(event Event) {
+		mu.Lock()
+		types = append(types, event.Type)
+		mu.Unlock()
+	})
+	return func() []EventType {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]EventType(nil), types...)
+	}
+}
+
+func TestEventsUpload(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		newFakeServer(t)
+		api := API{Key: testKey, Secret: testSecret, Events: NewEventBus()}
+		events := recordEvents(api.Events)
+
+		if _, err := api.UploadFile(strings.NewReader("content"), nil); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := events(), []EventType{EventUploadStarted, EventUploadCompleted}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got events %v, want %v", got, want)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		server := newFakeServer(t)
+		server.status = http.StatusTooManyRequests
+		server.response = ""
+		api := API{Key: testKey, Secret: testSecret, Events: NewEventBus()}
+		events := recordEvents(api.Events)
+
+		if _, err := api.UploadFile(strings.NewReader("content"), nil); err == nil {
+			t.Fatal("got no error")
+		}
+		if got, want := events(), []EventType{EventUploadStarted, EventUploadFailed, EventRateLimited}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got events %v, want %v", got, want)
+		}
+	})
+}
+
+func TestEventsRetry(t *testing.T) {
+	server := newFakeServer(t)
+	server.truncate = 2
+	api := API{Key: testKey, Secret: testSecret, Events: NewEventBus()}
+
+	var retries []Event
+	unsubscribe := api.Events.Subscribe(func(event Event) {
+		retries = append(retries, event)
+	})
+
+	if _, err := api.Get("files/list", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(retries) != 2 {
+		t.Fatalf("got events %+v, want two retries", retries)
+	}
+	if retries[0].Type != EventRetry || retries[1].Attempt != 2 || retries[0].Path != "files/list" {
+		t.Errorf("got events %+v, want two retries of files/list", retries)
+	}
+	var readErr *readError
+	if !errors.As(retries[0].Err, &readErr) {
+		t.Errorf("got error %v, want the read error that caused the retry", retries[0].Err)
+	}
+
+	unsubscribe()
+	server.truncate = 1
+	if _, err := api.Get("files/list", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(retries) != 2 {
+		t.Errorf("got %d events, want none after unsubscribing", len(retries)-2)
+	}
+}
+
+func TestEventBusZeroValue(t *testing.T) {
+	var bus EventBus
+	var got []Event
+	unsubscribe := bus.Subscribe(func(event Event) {
+		got = append(got, event)
+	})
+	bus.Publish(Event{Type: EventRetry})
+	unsubscribe()
+	bus.Publish(Event{Type: EventRetry})
+	if len(got) != 1 {
+		t.Errorf("got events %+v, want one before unsubscribing", got)
+	}
+}
@@ -0,0 +1,159 @@
+package publitio
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// File is a media file stored on Publitio.
+type File struct {
+	ID              string `json:"id"`
+	PublicID        string `json:"public_id"`
+	Folder          string `json:"folder"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	Tags            string `json:"tags"`
+	Type            string `json:"type"`
+	Extension       string `json:"extension"`
+	Size            int64  `json:"size"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	Privacy         int    `json:"privacy"`
+	OptionDownload  int    `json:"option_download"`
+	OptionTransform int    `json:"option_transform"`
+	OptionAd        int    `json:"option_ad"`
+	URLPreview      string `json:"url_preview"`
+	URLThumbnail    string `json:"url_thumbnail"`
+	URLDownload     string `json:"url_download"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// UploadFromURL makes Publitio fetch the file at remoteURL and returns the created file.
+// The remaining values (title, tags, folder, ...) are passed along as for UploadFile.
+func (api *API) UploadFromURL(ctx context.Context, remoteURL string, values url.Values, opts ...CallOption) (*File, error) {
+	values = copyValues(values)
+	values.Set("file_url", remoteURL)
+
+	res, err := api.UploadFileContext(ctx, nil, values, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFile(res)
+}
+
+// FileService provides typed access to the files endpoints.
+type FileService struct {
+	api *API
+}
+
+// Files returns a FileService that makes its calls through api.
+func (api *API) Files() *FileService {
+	return &FileService{api: api}
+}
+
+// Create uploads file with the given values (title, tags, folder, ...) and returns the created file.
+func (s *FileService) Create(ctx context.Context, file io.Reader, values url.Values, opts ...CallOption) (*File, error) {
+	res, err := s.api.UploadFileContext(ctx, file, values, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFile(res)
+}
+
+// Show returns the file with the given ID.
+func (s *FileService) Show(ctx context.Context, id string, opts ...CallOption) (*File, error) {
+	res, err := s.api.CallContext(ctx, "GET", "files/show/"+id, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFile(res)
+}
+
+// List returns a page of files; values such as offset, limit, order and filter_* are passed
+// along to the files/list endpoint. With WithFolderPath, only the files of that folder are
+// listed; unlike uploads, listings don't create missing folders.
+func (s *FileService) List(ctx context.Context, values url.Values, opts ...CallOption) ([]File, error) {
+	values, err := s.resolveFolder(ctx, values, opts)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.api.CallContext(ctx, "GET", "files/list", values, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Files []File `json:"files"`
+	}
+	err = decodeResponse(res, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Files, nil
+}
+
+// ListIter returns an iterator over all files matching values, following offset/limit
+// paging transparently until an empty page. The "limit" value, if any, sets the page size,
+// and "offset" the first file to return. WithFolderPath is resolved once, for the first page.
+func (s *FileService) ListIter(ctx context.Context, values url.Values, opts ...CallOption) *FileIterator {
+	started := false
+	return &FileIterator{
+		next: listPages(values, func(values url.Values) ([]File, error) {
+			if !started {
+				resolved, err := s.resolveFolder(ctx, values, opts)
+				if err != nil {
+					return nil, err
+				}
+				for key, v := range resolved {
+					values[key] = v
+				}
+				opts = append(opts[:len(opts):len(opts)], WithFolderPath(""))
+				started = true
+			}
+			return s.List(ctx, values, opts...)
+		}),
+	}
+}
+
+// resolveFolder sets the "folder" value to the ID of the folder given by WithFolderPath, if any.
+func (s *FileService) resolveFolder(ctx context.Context, values url.Values, opts []CallOption) (url.Values, error) {
+	o := newCallOptions(opts)
+	if o.folderPath == "" {
+		return values, nil
+	}
+	folderID, err := s.api.folderResolver().Resolve(ctx, o.folderPath)
+	if err != nil {
+		return nil, err
+	}
+	values = copyValues(values)
+	values.Set("folder", folderID)
+	return values, nil
+}
+
+// Update changes the given values (title, description, tags, privacy, ...) of the file with
+// the given ID and returns the updated file.
+func (s *FileService) Update(ctx context.Context, id string, values url.Values, opts ...CallOption) (*File, error) {
+	res, err := s.api.CallContext(ctx, "PUT", "files/update/"+id, values, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFile(res)
+}
+
+// Delete deletes the file with the given ID.
+func (s *FileService) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	_, err := s.api.CallContext(ctx, "DELETE", "files/delete/"+id, nil, opts...)
+	return err
+}
+
+func decodeFile(res Response) (*File, error) {
+	var file File
+	err := decodeResponse(res, &file)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
@@ -0,0 +1,206 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testFileJSON = `{
+	"success": true,
+	"code": 201,
+	"message": "File uploaded",
+	"id": "xbRdLbwS",
+	"public_id": "sample",
+	"folder": "",
+	"title": "Sample",
+	"description": "",
+	"tags": "nature sample",
+	"type": "image",
+	"extension": "jpg",
+	"size": 26940,
+	"width": 400,
+	"height": 300,
+	"privacy": 1,
+	"option_download": 1,
+	"option_transform": 1,
+	"option_ad": 0,
+	"url_preview": "https://media.publit.io/file/sample.jpg",
+	"url_thumbnail": "https://media.publit.io/file/w_300,h_200,c_fill/sample.jpg",
+	"url_download": "https://media.publit.io/download/sample.jpg?at=token",
+	"created_at": "2019-08-25 10:00:00"
+}`
+
+func TestUploadFromURL(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = testFileJSON
+	api := API{Key: testKey, Secret: testSecret}
+
+	file, err := api.UploadFromURL(context.Background(), "https://example.org/sample.jpg", url.Values{"title": {"Sample"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "xbRdLbwS" || file.Size != 26940 || file.Width != 400 || file.Privacy != 1 || file.Tags != "nature sample" {
+		t.Errorf("got %+v, want the decoded file", file)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/files/create" {
+		t.Errorf("got %s %s, want POST /v1/files/create", req.Method, req.Path)
+	}
+	if req.Query.Get("file_url") != "https://example.org/sample.jpg" || req.Query.Get("title") != "Sample" {
+		t.Errorf("got query %v, want file_url and title", req.Query)
+	}
+	if len(req.Body) != 0 {
+		t.Errorf("got body %q, want none", req.Body)
+	}
+}
+
+func TestFileService(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = testFileJSON
+	server.responses = map[string]string{
+		"/v1/files/list":            `{"success": true, "files_count": 2, "files": [{"id": "a", "size": 1}, {"id": "b", "size": 2}]}`,
+		"/v1/files/delete/xbRdLbwS": `{"success": true, "code": 200, "message": "File deleted"}`,
+	}
+	api := API{Key: testKey, Secret: testSecret}
+	files := api.Files()
+	ctx := context.Background()
+
+	file, err := files.Create(ctx, strings.NewReader("content"), url.Values{"title": {"Sample"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "xbRdLbwS" {
+		t.Errorf("got %+v, want the created file", file)
+	}
+	if req := server.lastRequest(t); req.Method != "POST" || req.Path != "/v1/files/create" {
+		t.Errorf("got %s %s, want POST /v1/files/create", req.Method, req.Path)
+	}
+
+	file, err = files.Show(ctx, "xbRdLbwS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.PublicID != "sample" {
+		t.Errorf("got %+v, want the shown file", file)
+	}
+	if req := server.lastRequest(t); req.Method != "GET" || req.Path != "/v1/files/show/xbRdLbwS" {
+		t.Errorf("got %s %s, want GET /v1/files/show/xbRdLbwS", req.Method, req.Path)
+	}
+
+	list, err := files.List(ctx, url.Values{"limit": {"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "a" || list[1].Size != 2 {
+		t.Errorf("got %+v, want two files", list)
+	}
+	if req := server.lastRequest(t); req.Query.Get("limit") != "2" {
+		t.Errorf("got query %v, want limit=2", req.Query)
+	}
+
+	file, err = files.Update(ctx, "xbRdLbwS", url.Values{"title": {"Renamed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "xbRdLbwS" {
+		t.Errorf("got %+v, want the updated file", file)
+	}
+	if req := server.lastRequest(t); req.Method != "PUT" || req.Path != "/v1/files/update/xbRdLbwS" || req.Query.Get("title") != "Renamed" {
+		t.Errorf("got %s %s?%v, want PUT /v1/files/update/xbRdLbwS with the title", req.Method, req.Path, req.Query)
+	}
+
+	err = files.Delete(ctx, "xbRdLbwS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "DELETE" || req.Path != "/v1/files/delete/xbRdLbwS" {
+		t.Errorf("got %s %s, want DELETE /v1/files/delete/xbRdLbwS", req.Method, req.Path)
+	}
+}
+
+func TestFileServiceListIter(t *testing.T) {
+	server := newFakeServer(t)
+	files := testFiles(2*listPageSize + 50)
+	server.respond = fileListResponder(files)
+	api := API{Key: testKey, Secret: testSecret}
+
+	it := api.Files().ListIter(context.Background(), url.Values{"order": {"date"}})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.File().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != len(files) {
+		t.Fatalf("got %d files, want %d", len(ids), len(files))
+	}
+	if ids[0] != "id000" || ids[len(ids)-1] != files[len(files)-1]["id"] {
+		t.Errorf("got files from %v to %v, want them in order", ids[0], ids[len(ids)-1])
+	}
+	if len(server.requests) != 4 {
+		t.Errorf("got %d requests, want 3 pages and an empty one", len(server.requests))
+	}
+	for _, req := range server.requests {
+		if req.Query.Get("order") != "date" {
+			t.Errorf("got query %v, want the order on every page", req.Query)
+		}
+	}
+}
+
+func TestFileServiceListIterCappedPages(t *testing.T) {
+	server := newFakeServer(t)
+	files := testFiles(95)
+	server.respond = func(req recordedRequest) string {
+		// Like the Publitio server, cap pages at a maximum size below the requested limit
+		req.Query.Set("limit", "30")
+		return fileListResponder(files)(req)
+	}
+	api := API{Key: testKey, Secret: testSecret}
+
+	it := api.Files().ListIter(context.Background(), url.Values{"limit": {"50"}})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != len(files) {
+		t.Errorf("got %d files, want all %d despite the capped pages", n, len(files))
+	}
+}
+
+func TestFileServiceListIterPageSize(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		if req.Path == "/v1/folders/tree" {
+			return testTreeJSON
+		}
+		return fileListResponder(testFiles(25))(req)
+	}
+	api := API{Key: testKey, Secret: testSecret}
+
+	it := api.Files().ListIter(context.Background(), url.Values{"offset": {"5"}, "limit": {"10"}}, WithFolderPath("docs"))
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 20 {
+		t.Errorf("got %d files, want the 20 after the offset", n)
+	}
+	if trees := server.countRequests("/v1/folders/tree"); trees != 1 {
+		t.Errorf("got %d folder lookups, want 1", trees)
+	}
+	for _, req := range server.requests {
+		if req.Path == "/v1/files/list" && (req.Query.Get("limit") != "10" || req.Query.Get("folder") != "f3") {
+			t.Errorf("got query %v, want pages of 10 files of folder f3", req.Query)
+		}
+	}
+}
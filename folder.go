@@ -0,0 +1,126 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+)
+
+// Folder is a folder on Publitio.
+type Folder struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	ParentID  string   `json:"parent_id"`
+	Path      string   `json:"path"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+	Children  []Folder `json:"children"` // Subfolders, only filled in by FolderService.Tree
+}
+
+// FolderService provides typed access to the folders endpoints.
+type FolderService struct {
+	api *API
+}
+
+// Folders returns a FolderService that makes its calls through api.
+func (api *API) Folders() *FolderService {
+	return &FolderService{api: api}
+}
+
+// Create creates a folder with the given name inside the folder with ID parentID,
+// or at the top level if parentID is empty, and returns it.
+func (s *FolderService) Create(ctx context.Context, name, parentID string, opts ...CallOption) (*Folder, error) {
+	folder, err := s.create(ctx, name, parentID, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return folder, nil
+}
+
+// create is like Create, but leaves API.FolderResolver alone, so the resolver can use it.
+func (s *FolderService) create(ctx context.Context, name, parentID string, opts []CallOption) (*Folder, error) {
+	values := url.Values{"name": {name}}
+	if parentID != "" {
+		values.Set("parent_id", parentID)
+	}
+	res, err := s.api.CallContext(ctx, "POST", "folders/create", values, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFolder(res)
+}
+
+// Show returns the folder with the given ID.
+func (s *FolderService) Show(ctx context.Context, id string, opts ...CallOption) (*Folder, error) {
+	res, err := s.api.CallContext(ctx, "GET", "folders/show/"+id, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFolder(res)
+}
+
+// List returns the folders; values such as parent_id are passed along to the folders/list endpoint.
+func (s *FolderService) List(ctx context.Context, values url.Values, opts ...CallOption) ([]Folder, error) {
+	res, err := s.api.CallContext(ctx, "GET", "folders/list", values, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFolders(res)
+}
+
+// Update changes the given values (name, parent_id) of the folder with the given ID
+// and returns the updated folder.
+func (s *FolderService) Update(ctx context.Context, id string, values url.Values, opts ...CallOption) (*Folder, error) {
+	res, err := s.api.CallContext(ctx, "PUT", "folders/update/"+id, values, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return decodeFolder(res)
+}
+
+// Delete deletes the folder with the given ID.
+func (s *FolderService) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	_, err := s.api.CallContext(ctx, "DELETE", "folders/delete/"+id, nil, opts...)
+	if err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Tree returns the top-level folders with their subfolders filled in as Children.
+func (s *FolderService) Tree(ctx context.Context, opts ...CallOption) ([]Folder, error) {
+	res, err := s.api.CallContext(ctx, "GET", "folders/tree", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFolders(res)
+}
+
+// invalidate drops the folder tree cached by API.FolderResolver, if any.
+func (s *FolderService) invalidate() {
+	if s.api.FolderResolver != nil {
+		s.api.FolderResolver.Invalidate()
+	}
+}
+
+func decodeFolder(res Response) (*Folder, error) {
+	var folder Folder
+	err := decodeResponse(res, &folder)
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func decodeFolders(res Response) ([]Folder, error) {
+	var list struct {
+		Folders []Folder `json:"folders"`
+	}
+	err := decodeResponse(res, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Folders, nil
+}
@@ -0,0 +1,73 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+const testTreeJSON = `{
+	"success": true,
+	"folders": [
+		{"id": "f1", "name": "marketing", "path": "marketing", "children": [
+			{"id": "f2", "name": "2025", "parent_id": "f1", "path": "marketing/2025", "children": []}
+		]},
+		{"id": "f3", "name": "docs", "path": "docs", "children": []}
+	]
+}`
+
+func TestFolderService(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "id": "f2", "name": "2025", "parent_id": "f1", "path": "marketing/2025"}`
+	server.responses = map[string]string{
+		"/v1/folders/list": `{"success": true, "folders": [{"id": "f1", "name": "marketing"}, {"id": "f3", "name": "docs"}]}`,
+		"/v1/folders/tree": testTreeJSON,
+	}
+	api := API{Key: testKey, Secret: testSecret}
+	folders := api.Folders()
+	ctx := context.Background()
+
+	folder, err := folders.Create(ctx, "2025", "f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.ID != "f2" || folder.Path != "marketing/2025" {
+		t.Errorf("got %+v, want the created folder", folder)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/folders/create" || req.Query.Get("name") != "2025" || req.Query.Get("parent_id") != "f1" {
+		t.Errorf("got %s %s?%v, want POST /v1/folders/create with the name and parent", req.Method, req.Path, req.Query)
+	}
+
+	list, err := folders.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].Name != "docs" {
+		t.Errorf("got %+v, want two folders", list)
+	}
+
+	_, err = folders.Update(ctx, "f2", url.Values{"name": {"2026"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "PUT" || req.Path != "/v1/folders/update/f2" || req.Query.Get("name") != "2026" {
+		t.Errorf("got %s %s?%v, want PUT /v1/folders/update/f2 with the name", req.Method, req.Path, req.Query)
+	}
+
+	err = folders.Delete(ctx, "f2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "DELETE" || req.Path != "/v1/folders/delete/f2" {
+		t.Errorf("got %s %s, want DELETE /v1/folders/delete/f2", req.Method, req.Path)
+	}
+
+	tree, err := folders.Tree(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 2 || len(tree[0].Children) != 1 || tree[0].Children[0].Path != "marketing/2025" {
+		t.Errorf("got %+v, want the folder tree", tree)
+	}
+}
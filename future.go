@@ -0,0 +1,50 @@
+package publitio
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// UploadFuture is a handle to an upload running in the background.
+type UploadFuture struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	result Response
+	err    error
+}
+
+// UploadFileAsync starts uploading a file in the background, like UploadFile, and returns
+// immediately. The reader must not be used by the caller until the upload is done.
+func (api *API) UploadFileAsync(file io.Reader, values url.Values) *UploadFuture {
+	ctx, cancel := context.WithCancel(context.Background())
+	future := &UploadFuture{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(future.done)
+		defer cancel()
+		future.result, future.err = api.UploadFileContext(ctx, file, values)
+	}()
+
+	return future
+}
+
+// Done returns a channel that is closed once the upload has finished, successfully or not.
+func (f *UploadFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the upload to finish and returns its result.
+func (f *UploadFuture) Result() (Response, error) {
+	<-f.done
+	return f.result, f.err
+}
+
+// Cancel aborts the upload if it is still running; Result then returns an error.
+// Cancelling a finished upload has no effect.
+func (f *UploadFuture) Cancel() {
+	f.cancel()
+}
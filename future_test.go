@@ -0,0 +1,50 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadFileAsync(t *testing.T) {
+	newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	futures := make([]*UploadFuture, 5)
+	for i := range futures {
+		futures[i] = api.UploadFileAsync(strings.NewReader("content"), nil)
+	}
+	for _, future := range futures {
+		<-future.Done()
+		result, err := future.Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result == nil {
+			t.Error("got no result")
+		}
+	}
+}
+
+func TestUploadFileAsyncCancel(t *testing.T) {
+	started := make(chan struct{})
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	defer func() { http.DefaultTransport = transport }()
+
+	api := API{Key: testKey, Secret: testSecret}
+	future := api.UploadFileAsync(strings.NewReader("content"), nil)
+	<-started
+	future.Cancel()
+
+	_, err := future.Result()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,41 @@
+package publitio
+
+import (
+	"context"
+	"sync"
+)
+
+// group runs functions concurrently and cancels its context on the first error, like
+// golang.org/x/sync/errgroup, which this package doesn't depend on.
+type group struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+	once   sync.Once
+	err    error
+}
+
+func newGroup(ctx context.Context) (*group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &group{cancel: cancel}, ctx
+}
+
+// Go calls f in a new goroutine. The first non-nil error cancels the group's context.
+func (g *group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait waits for all functions to return and returns the first error, if any.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,33 @@
+package publitio
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// HealthStatus is the result of a health check.
+type HealthStatus struct {
+	Reachable  bool          // Whether the server responded at all
+	StatusCode int           // HTTP status code of the response, 0 if unreachable
+	Latency    time.Duration // Time until the response was parsed
+}
+
+// Health performs a cheap authenticated call, listing a single file, and reports whether
+// the server could be reached and how long the call took. Like any other call, it is
+// subject to API.Policy. The returned error is nil only if the call succeeded, so it can
+// be used directly as a readiness check; the status is filled in either way.
+func (api *API) Health(ctx context.Context) (HealthStatus, error) {
+	start := time.Now()
+	raw, err := api.CallRaw(ctx, "GET", "files/list", url.Values{"limit": {"1"}}, WithoutRetry())
+	health := HealthStatus{
+		Reachable:  raw != nil,
+		StatusCode: raw.status(),
+		Latency:    time.Since(start),
+	}
+	if err != nil {
+		return health, fmt.Errorf("error while checking health: %w", err)
+	}
+	return health, nil
+}
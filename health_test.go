@@ -0,0 +1,55 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	health, err := api.Health(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !health.Reachable || health.StatusCode != 200 || health.Latency <= 0 {
+		t.Errorf("got %+v, want a reachable server with status 200 and a latency", health)
+	}
+	if req := server.lastRequest(t); req.Path != "/v1/files/list" || req.Query.Get("limit") != "1" {
+		t.Errorf("got request to %s?%v, want a listing of a single file", req.Path, req.Query)
+	}
+}
+
+func TestHealthCancelled(t *testing.T) {
+	newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	health, err := api.Health(ctx)
+	if err == nil {
+		t.Fatal("got no error for a cancelled context")
+	}
+	if health.Reachable {
+		t.Errorf("got %+v, want an unreachable server", health)
+	}
+}
+
+func TestHealthPolicy(t *testing.T) {
+	server := newFakeServer(t)
+	denied := errors.New("denied")
+	api := API{Key: testKey, Secret: testSecret, Policy: func(method, path string, values url.Values) error {
+		return denied
+	}}
+
+	health, err := api.Health(context.Background())
+	if !errors.Is(err, denied) {
+		t.Errorf("got error %v, want the policy veto", err)
+	}
+	if health.Reachable || len(server.requests) != 0 {
+		t.Errorf("got %+v and %d requests, want no request", health, len(server.requests))
+	}
+}
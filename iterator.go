@@ -0,0 +1,198 @@
+package publitio
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// FileIterator steps through a listing of files, fetching them as needed. It is returned
+// by FileService.ListIter and ListFilesRecursive:
+//
+//	files := api.ListFilesRecursive(ctx, "marketing")
+//	defer files.Close()
+//	for files.Next() {
+//		file := files.File()
+//		...
+//	}
+//	if err := files.Err(); err != nil {
+//		...
+//	}
+type FileIterator struct {
+	next  func() (File, error) // Returns io.EOF after the last file
+	close func()
+
+	file File
+	err  error
+	done bool
+}
+
+// Next advances to the next file, which is then available through File. It returns false
+// when there are no more files or an error occurred, which is reported by Err.
+func (it *FileIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	file, err := it.next()
+	if err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		it.Close()
+		return false
+	}
+	it.file = file
+	return true
+}
+
+// File returns the current file.
+func (it *FileIterator) File() File {
+	return it.file
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *FileIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iteration and releases its resources. It is called automatically once
+// Next returns false, but must be called if the iteration is abandoned early.
+func (it *FileIterator) Close() {
+	if it.done {
+		return
+	}
+	it.done = true
+	if it.close != nil {
+		it.close()
+	}
+}
+
+const (
+	listPageSize             = 100
+	listRecursiveConcurrency = 4
+)
+
+// listPages returns a function that returns the items listed by list one at a time, and
+// io.EOF after the last one. It calls list with values for one page after the other, from
+// the "offset" value on, in pages of the "limit" value or listPageSize. Paging only stops at
+// an empty page, since the server may return fewer items than asked for, up to its maximum.
+func listPages[T any](values url.Values, list func(values url.Values) ([]T, error)) func() (T, error) {
+	values = copyValues(values)
+	pageSize := listPageSize
+	if limit, err := strconv.Atoi(values.Get("limit")); err == nil && limit > 0 {
+		pageSize = limit
+	}
+	offset, _ := strconv.Atoi(values.Get("offset"))
+
+	var page []T
+	last := false
+	return func() (T, error) {
+		var zero T
+		for len(page) == 0 {
+			if last {
+				return zero, io.EOF
+			}
+			values.Set("offset", strconv.Itoa(offset))
+			values.Set("limit", strconv.Itoa(pageSize))
+			items, err := list(values)
+			if err != nil {
+				return zero, err
+			}
+			page = items
+			offset += len(items)
+			last = len(items) == 0
+		}
+
+		item := page[0]
+		page = page[1:]
+		return item, nil
+	}
+}
+
+// ListFilesRecursive lists the files of the folder at the slash-separated folderPath and
+// all of its subfolders, each file once. The folders are looked up through API.FolderResolver
+// and listed concurrently, a few at a time, so the order of the files is unspecified. The
+// empty path is the top level, which lists every file with a single paged listing.
+func (api *API) ListFilesRecursive(ctx context.Context, folderPath string) *FileIterator {
+	if cleanFolderPath(folderPath) == "" {
+		return api.Files().ListIter(ctx, nil)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	files := make(chan File)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(files)
+
+		folderIDs, err := api.folderResolver().subtree(ctx, folderPath)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		g, ctx := newGroup(ctx)
+		queue := make(chan string)
+		for i := 0; i < listRecursiveConcurrency; i++ {
+			g.Go(func() error {
+				for folderID := range queue {
+					if err := api.listFolder(ctx, folderID, files); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	feed:
+		for _, folderID := range folderIDs {
+			select {
+			case queue <- folderID:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(queue)
+
+		if err := g.Wait(); err != nil {
+			errc <- err
+		}
+	}()
+
+	return &FileIterator{
+		next: func() (File, error) {
+			file, ok := <-files
+			if !ok {
+				select {
+				case err := <-errc:
+					return File{}, err
+				default:
+					return File{}, io.EOF
+				}
+			}
+			return file, nil
+		},
+		close: func() {
+			cancel()
+			// Let the producer finish, so no goroutine outlives the iterator
+			for range files {
+			}
+		},
+	}
+}
+
+// listFolder sends every file of the folder with the given ID to files. The top level,
+// whose ID is empty, is listed without a folder, which lists the files of every folder,
+// so only the files outside of any folder are sent.
+func (api *API) listFolder(ctx context.Context, folderID string, files chan<- File) error {
+	it := api.Files().ListIter(ctx, url.Values{"folder": {folderID}})
+	defer it.Close()
+	for it.Next() {
+		select {
+		case files <- it.File():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return it.Err()
+}
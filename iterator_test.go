@@ -0,0 +1,134 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// folderFilesResponder serves testTreeJSON and the given files per folder ID.
+func folderFilesResponder(files map[string][]map[string]interface{}) func(req recordedRequest) string {
+	return func(req recordedRequest) string {
+		if req.Path == "/v1/folders/tree" {
+			return testTreeJSON
+		}
+		return fileListResponder(files[req.Query.Get("folder")])(req)
+	}
+}
+
+func TestListFilesRecursive(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderFilesResponder(map[string][]map[string]interface{}{
+		"f1": testFiles(listPageSize + 50),
+		"f2": testFiles(2),
+		"f3": testFiles(5),
+	})
+	api := API{Key: testKey, Secret: testSecret}
+
+	files := api.ListFilesRecursive(context.Background(), "marketing")
+	n := 0
+	for files.Next() {
+		if files.File().ID == "" {
+			t.Errorf("got file %+v without an ID", files.File())
+		}
+		n++
+	}
+	if err := files.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := listPageSize + 50 + 2; n != want {
+		t.Errorf("got %d files, want %d", n, want)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, req := range server.requests {
+		if req.Path == "/v1/files/list" && req.Query.Get("folder") == "f3" {
+			t.Errorf("listed folder f3 outside of the subtree")
+		}
+	}
+}
+
+func TestListFilesRecursiveTopLevel(t *testing.T) {
+	server := newFakeServer(t)
+	nested := map[string][]map[string]interface{}{
+		"f1": testFiles(listPageSize + 50),
+		"f2": testFiles(2),
+		"f3": testFiles(5),
+	}
+	// Like the Publitio server, a listing without a folder returns the files of every folder
+	all := testFiles(3)
+	for folderID, files := range nested {
+		for i, file := range files {
+			file["id"] = fmt.Sprintf("%s-%03d", folderID, i)
+			file["folder"] = folderID
+			all = append(all, file)
+		}
+	}
+	nested[""] = all
+	server.respond = folderFilesResponder(nested)
+	api := API{Key: testKey, Secret: testSecret}
+
+	files := api.ListFilesRecursive(context.Background(), "")
+	seen := make(map[string]bool)
+	for files.Next() {
+		if seen[files.File().ID] {
+			t.Errorf("got file %s twice", files.File().ID)
+		}
+		seen[files.File().ID] = true
+	}
+	if err := files.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != len(all) {
+		t.Errorf("got %d files, want %d", len(seen), len(all))
+	}
+
+	// A single paged listing of every file, without looking up folders
+	if n := server.countRequests("/v1/folders/tree"); n != 0 {
+		t.Errorf("got %d tree requests, want none", n)
+	}
+	for _, req := range server.requests {
+		if folder := req.Query.Get("folder"); folder != "" {
+			t.Errorf("got a listing of folder %s, want only unfiltered listings", folder)
+		}
+	}
+	if want := (len(all) + listPageSize - 1) / listPageSize; server.countRequests("/v1/files/list") > want+1 {
+		t.Errorf("got %d listings, want at most %d", server.countRequests("/v1/files/list"), want+1)
+	}
+}
+
+func TestListFilesRecursiveNotFound(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderFilesResponder(nil)
+	api := API{Key: testKey, Secret: testSecret}
+
+	files := api.ListFilesRecursive(context.Background(), "marketing/2026")
+	if files.Next() {
+		t.Fatal("got a file from a missing folder")
+	}
+	if err := files.Err(); !errors.Is(err, ErrFolderNotFound) {
+		t.Errorf("got error %v, want ErrFolderNotFound", err)
+	}
+}
+
+func TestListFilesRecursiveClose(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderFilesResponder(map[string][]map[string]interface{}{
+		"f1": testFiles(3 * listPageSize),
+	})
+	api := API{Key: testKey, Secret: testSecret}
+
+	files := api.ListFilesRecursive(context.Background(), "marketing")
+	if !files.Next() {
+		t.Fatal(files.Err())
+	}
+	files.Close()
+	if files.Next() {
+		t.Error("got a file after Close")
+	}
+	if err := files.Err(); err != nil {
+		t.Errorf("got error %v after Close", err)
+	}
+}
@@ -0,0 +1,167 @@
+package publitio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// multipartBody streams a file as the only part of a multipart/form-data request body,
+// without buffering the file in memory.
+type multipartBody struct {
+	io.Reader
+	contentType string
+	length      int64 // Total body length, -1 if the file size is unknown
+	fileName    string
+
+	file     io.Reader
+	fileSize int64 // -1 if unknown
+	sent     int64 // Number of file bytes read so far
+	progress ProgressFunc
+	readErr  error // First error returned by file, other than io.EOF
+}
+
+// ProgressFunc is called as an upload progresses with the number of file bytes sent so far
+// and the total file size, which is -1 if it is unknown.
+type ProgressFunc func(bytesSent, total int64)
+
+func newMultipartBody(file io.Reader, fieldName string, progress ProgressFunc) (*multipartBody, error) {
+	file, fileName, contentType := describeFile(file)
+
+	// The part header and the closing boundary are small; only the file is streamed.
+	envelope := &bytes.Buffer{}
+	multipartWriter := multipart.NewWriter(envelope)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(fieldName), escapeQuotes(fileName)))
+	header.Set("Content-Type", contentType)
+	_, err := multipartWriter.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating multipart writer: %v", err)
+	}
+	headerLength := envelope.Len()
+	err = multipartWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error while closing the multipart writer: %v", err)
+	}
+	partHeader := envelope.Bytes()[:headerLength]
+	trailer := envelope.Bytes()[headerLength:]
+
+	body := &multipartBody{
+		contentType: multipartWriter.FormDataContentType(),
+		length:      -1,
+		fileName:    fileName,
+		file:        file,
+		fileSize:    readerSize(file),
+		progress:    progress,
+	}
+	body.Reader = io.MultiReader(bytes.NewReader(partHeader), readerFunc(body.readFile), bytes.NewReader(trailer))
+	if body.fileSize >= 0 {
+		body.length = int64(len(partHeader)) + body.fileSize + int64(len(trailer))
+	}
+	return body, nil
+}
+
+func (b *multipartBody) readFile(p []byte) (int, error) {
+	n, err := b.file.Read(p)
+	if n > 0 {
+		b.sent += int64(n)
+		if b.progress != nil {
+			b.progress(b.sent, b.fileSize)
+		}
+	}
+	if err != nil && err != io.EOF && b.readErr == nil {
+		b.readErr = err
+	}
+	return n, err
+}
+
+// NamedFile wraps r so that uploads send it with the given filename and content type,
+// which Publitio uses to detect the file type. An empty content type is derived from the
+// filename's extension. Files opened with os.Open don't need wrapping; their name is used.
+func NamedFile(r io.Reader, name, contentType string) io.Reader {
+	return &namedFile{Reader: r, name: name, contentType: contentType}
+}
+
+type namedFile struct {
+	io.Reader
+	name        string
+	contentType string
+}
+
+// defaultFileName is the multipart filename of uploads whose name is unknown.
+const defaultFileName = "file"
+
+// describeFile returns the reader to stream, the filename and the content type of an upload.
+func describeFile(file io.Reader) (io.Reader, string, string) {
+	name := defaultFileName
+	contentType := ""
+	switch f := file.(type) {
+	case *namedFile:
+		file = f.Reader
+		if f.name != "" {
+			name = filepath.Base(f.name)
+		}
+		contentType = f.contentType
+	case interface{ Name() string }: // *os.File
+		if f.Name() != "" {
+			name = filepath.Base(f.Name())
+		}
+	}
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return file, name, contentType
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes escapes a Content-Disposition parameter the way mime/multipart does.
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
+
+// readerSize returns the number of bytes left in r, or -1 if it can't be determined
+// without reading.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }: // bytes.Reader, bytes.Buffer, strings.Reader
+		return int64(r.Len())
+	case io.Seeker:
+		current, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		_, err = r.Seek(current, io.SeekStart)
+		if err != nil {
+			return -1
+		}
+		return end - current
+	case interface{ Stat() (os.FileInfo, error) }:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		return info.Size()
+	}
+	return -1
+}
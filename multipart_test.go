@@ -0,0 +1,52 @@
+package publitio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	newFakeServer(t)
+	content := bytes.Repeat([]byte("x"), 100000)
+
+	for _, known := range []bool{true, false} {
+		var calls int
+		var lastSent, lastTotal int64
+		api := NewAPI(testKey, testSecret, WithProgress(func(sent, total int64) {
+			if sent < lastSent {
+				t.Errorf("progress went back from %d to %d", lastSent, sent)
+			}
+			calls++
+			lastSent, lastTotal = sent, total
+		}))
+
+		reader := bytes.NewReader(content)
+		var err error
+		if known {
+			_, err = api.UploadFile(reader, nil)
+		} else {
+			_, err = api.UploadFile(onlyReader{reader}, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantTotal := int64(len(content))
+		if !known {
+			wantTotal = -1
+		}
+		if calls == 0 || lastSent != int64(len(content)) || lastTotal != wantTotal {
+			t.Errorf("got %d calls ending at %d of %d, want progress up to %d of %d", calls, lastSent, lastTotal, len(content), wantTotal)
+		}
+	}
+}
+
+func TestProgressNotCalledWithoutContent(t *testing.T) {
+	newFakeServer(t)
+	api := NewAPI(testKey, testSecret, WithProgress(func(sent, total int64) {
+		t.Error("progress reported for an upload without content")
+	}))
+	api.UploadFile(nil, nil)
+	api.UploadFile(strings.NewReader(""), nil)
+}
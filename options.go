@@ -0,0 +1,117 @@
+package publitio
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Option configures an API created by NewAPI.
+type Option func(api *API)
+
+// NewAPI creates an API with the given credentials, configured by the given options.
+// It is equivalent to setting the corresponding fields of an API value.
+func NewAPI(key, secret string, options ...Option) *API {
+	api := &API{Key: key, Secret: secret}
+	for _, option := range options {
+		option(api)
+	}
+	return api
+}
+
+// WithHTTPClient makes the API use client for all requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(api *API) {
+		api.HTTPClient = client
+	}
+}
+
+// WithBaseURL makes the API send requests to baseURL instead of DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(api *API) {
+		api.BaseURL = baseURL
+	}
+}
+
+// WithTimeout sets a time limit for each request, including reading the response.
+// It applies to a copy of the HTTP client, so a client given to WithHTTPClient is not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(api *API) {
+		client := http.Client{}
+		if api.HTTPClient != nil {
+			client = *api.HTTPClient
+		}
+		client.Timeout = timeout
+		api.HTTPClient = &client
+	}
+}
+
+// WithConnectionPool gives the API its own connection pool keeping at most maxIdlePerHost
+// idle connections per host, each for at most idleTimeout. It applies to a copy of the HTTP
+// client with a clone of its transport, so a client given to WithHTTPClient is not modified.
+// Without this option, APIs share http.DefaultTransport.
+//
+// The option has no effect if the client's transport, or http.DefaultTransport for a client
+// without one, is not an *http.Transport, such as a logging or authenticating RoundTripper;
+// configure the pool of the transport it wraps instead.
+func WithConnectionPool(maxIdlePerHost int, idleTimeout time.Duration) Option {
+	return func(api *API) {
+		client := http.Client{}
+		if api.HTTPClient != nil {
+			client = *api.HTTPClient
+		}
+
+		base := client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		transport, ok := base.(*http.Transport)
+		if !ok {
+			return
+		}
+		transport = transport.Clone()
+		transport.MaxIdleConnsPerHost = maxIdlePerHost
+		if transport.MaxIdleConns != 0 && transport.MaxIdleConns < maxIdlePerHost {
+			transport.MaxIdleConns = maxIdlePerHost
+		}
+		transport.IdleConnTimeout = idleTimeout
+
+		client.Transport = transport
+		api.HTTPClient = &client
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(api *API) {
+		api.UserAgent = userAgent
+	}
+}
+
+// WithUserAgentSuffix appends suffix to the User-Agent header sent with every request.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(api *API) {
+		api.UserAgentSuffix = suffix
+	}
+}
+
+// WithDebugDump makes the API write sanitized request and response dumps to w.
+func WithDebugDump(w io.Writer) Option {
+	return func(api *API) {
+		api.DebugDump = w
+	}
+}
+
+// WithProgress makes the API report the progress of file uploads to progress.
+func WithProgress(progress ProgressFunc) Option {
+	return func(api *API) {
+		api.Progress = progress
+	}
+}
+
+// WithUseNumber makes the API decode numbers in responses as json.Number, see API.UseNumber.
+func WithUseNumber() Option {
+	return func(api *API) {
+		api.UseNumber = true
+	}
+}
@@ -0,0 +1,170 @@
+package publitio
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAPI(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checkSignature(r.URL.Query()); err != nil {
+			t.Error(err)
+		}
+		if r.URL.Path != "/v1/files/list" {
+			t.Errorf("got path %s, want /v1/files/list", r.URL.Path)
+		}
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{}
+	var dump bytes.Buffer
+	api := NewAPI(testKey, testSecret,
+		WithHTTPClient(client),
+		WithTimeout(time.Minute),
+		WithBaseURL(server.URL+"/v1"),
+		WithUserAgent("my-app/1.0"),
+		WithDebugDump(&dump))
+
+	if _, err := api.Get("files/list", nil); err != nil {
+		t.Fatal(err)
+	}
+	if userAgent != "my-app/1.0" {
+		t.Errorf("got User-Agent %q, want %q", userAgent, "my-app/1.0")
+	}
+	if api.HTTPClient.Timeout != time.Minute {
+		t.Errorf("got timeout %v, want %v", api.HTTPClient.Timeout, time.Minute)
+	}
+	if client.Timeout != 0 {
+		t.Error("WithTimeout modified the client given to WithHTTPClient")
+	}
+	if dump.Len() == 0 {
+		t.Error("nothing was dumped")
+	}
+}
+
+func TestHTTPClientUsedForAllRequests(t *testing.T) {
+	server := newFakeServer(t)
+	server.responses = map[string]string{
+		"/v1/files/show/fileId": `{"success": true, "public_id": "file", "url_download": "https://media.publit.io/download/file"}`,
+	}
+	server.media = map[string]string{"/download/file": "content"}
+
+	// The fake server is reached through http.DefaultTransport; count what goes through the client
+	var requests int
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	api := NewAPI(testKey, testSecret, WithHTTPClient(client))
+
+	api.Get("files/list", nil)
+	api.UploadFile(strings.NewReader("content"), nil)
+	api.UploadFile(nil, url.Values{"file_url": {"https://example.org"}})
+	if err := api.DownloadArchive(context.Background(), []string{"fileId"}, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := len(server.requests) + 1; requests != want {
+		t.Errorf("got %d requests through the client, want %d", requests, want)
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		path    string
+		want    string
+	}{
+		{"", "files/list", "https://api.publit.io/v1/files/list"},
+		{"", "/files/list", "https://api.publit.io/v1/files/list"},
+		{"http://localhost:8080/v1", "files/list", "http://localhost:8080/v1/files/list"},
+		{"http://localhost:8080/v1/", "/files/list", "http://localhost:8080/v1/files/list"},
+		{"https://staging.example.org", "files/show/fileId", "https://staging.example.org/files/show/fileId"},
+	}
+
+	for _, test := range tests {
+		api := API{Key: testKey, Secret: testSecret, BaseURL: test.baseURL}
+		got, err := api.publitioURL(test.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(got, test.want+"?") {
+			t.Errorf("base URL %q and path %q: got %s, want %s?...", test.baseURL, test.path, got, test.want)
+		}
+	}
+}
+
+func TestWithConnectionPool(t *testing.T) {
+	client := &http.Client{Timeout: time.Minute}
+	api := NewAPI(testKey, testSecret, WithHTTPClient(client), WithConnectionPool(32, time.Hour))
+
+	transport, ok := api.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("got transport %T, want *http.Transport", api.HTTPClient.Transport)
+	}
+	if transport == http.DefaultTransport {
+		t.Error("the default transport is shared")
+	}
+	if transport.MaxIdleConnsPerHost != 32 || transport.IdleConnTimeout != time.Hour {
+		t.Errorf("got %d idle connections per host for %v, want 32 for %v", transport.MaxIdleConnsPerHost, transport.IdleConnTimeout, time.Hour)
+	}
+	if api.HTTPClient.Timeout != time.Minute {
+		t.Error("the client's timeout was lost")
+	}
+	if client.Transport != nil {
+		t.Error("WithConnectionPool modified the client given to WithHTTPClient")
+	}
+}
+
+func TestWithConnectionPoolCustomTransport(t *testing.T) {
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	api := NewAPI(testKey, testSecret, WithHTTPClient(client), WithConnectionPool(32, time.Hour))
+
+	if api.HTTPClient != client {
+		t.Errorf("got client %+v, want the given client left alone", api.HTTPClient)
+	}
+	if _, ok := api.HTTPClient.Transport.(roundTripperFunc); !ok {
+		t.Errorf("got transport %T, want the custom transport", api.HTTPClient.Transport)
+	}
+}
+
+func TestDefaultHTTPClientIsShared(t *testing.T) {
+	a, b := API{}, API{}
+	if a.httpClient() != b.httpClient() {
+		t.Error("APIs without an HTTPClient don't share a client")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	server := newFakeServer(t)
+
+	tests := []struct {
+		api  *API
+		want string
+	}{
+		{NewAPI(testKey, testSecret), "publitio-go/" + Version},
+		{NewAPI(testKey, testSecret, WithUserAgentSuffix("my-app/2.3")), "publitio-go/" + Version + " my-app/2.3"},
+		{NewAPI(testKey, testSecret, WithUserAgent("custom"), WithUserAgentSuffix("my-app/2.3")), "custom my-app/2.3"},
+	}
+	for _, test := range tests {
+		if got := test.api.UserAgentString(); got != test.want {
+			t.Errorf("got UserAgentString %q, want %q", got, test.want)
+		}
+		test.api.UploadFile(strings.NewReader("content"), nil)
+		if got := server.lastRequest(t).Header.Get("User-Agent"); got != test.want {
+			t.Errorf("got User-Agent %q, want %q", got, test.want)
+		}
+	}
+}
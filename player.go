@@ -0,0 +1,136 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+)
+
+// Player is a configured video player on Publitio.
+type Player struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Skin            string `json:"skin"`
+	Color           string `json:"color"`
+	BackgroundColor string `json:"background_color"`
+	AutoPlay        int    `json:"auto_play"`
+	AdTagID         string `json:"adtag_id"`
+	LogoURL         string `json:"logo_url"`
+	LogoPosition    string `json:"logo_position"`
+	LogoLink        string `json:"logo_link"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// PlayerOptions are the settings of a player. Empty fields are left out, so they keep their
+// default when creating a player and their current value when updating one.
+type PlayerOptions struct {
+	Name            string     // Name of the player, required when creating one
+	Skin            string     // Player skin, for example "blue", "red", "green" or "dark"
+	Color           string     // Color of the controls as a hex code, for example "#ff0000", overriding the skin
+	BackgroundColor string     // Color of the control bar as a hex code, overriding the skin
+	AutoPlay        *bool      // Whether videos start playing when loaded
+	AdTagID         string     // ID of the ad tag to show
+	LogoURL         string     // URL of a logo overlaid on videos
+	LogoPosition    string     // Corner of the logo, for example "top-left" or "bottom-right"
+	LogoLink        string     // URL opened when the logo is clicked
+	Values          url.Values // Any other player parameters, passed along as they are
+}
+
+func (o PlayerOptions) values() url.Values {
+	values := copyValues(o.Values)
+	for key, value := range map[string]string{
+		"name":             o.Name,
+		"skin":             o.Skin,
+		"color":            o.Color,
+		"background_color": o.BackgroundColor,
+		"adtag_id":         o.AdTagID,
+		"logo_url":         o.LogoURL,
+		"logo_position":    o.LogoPosition,
+		"logo_link":        o.LogoLink,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if o.AutoPlay != nil {
+		values.Set("auto_play", boolValue(*o.AutoPlay))
+	}
+	return values
+}
+
+// PlayerService provides typed access to the players endpoints.
+type PlayerService struct {
+	api *API
+}
+
+// Players returns a PlayerService that makes its calls through api.
+func (api *API) Players() *PlayerService {
+	return &PlayerService{api: api}
+}
+
+// Create creates a player with the given settings and returns it.
+func (s *PlayerService) Create(ctx context.Context, player PlayerOptions, opts ...CallOption) (*Player, error) {
+	res, err := s.api.CallContext(ctx, "POST", "players/create", player.values(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodePlayer(res)
+}
+
+// Show returns the player with the given ID.
+func (s *PlayerService) Show(ctx context.Context, id string, opts ...CallOption) (*Player, error) {
+	res, err := s.api.CallContext(ctx, "GET", "players/show/"+id, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodePlayer(res)
+}
+
+// List returns all players.
+func (s *PlayerService) List(ctx context.Context, opts ...CallOption) ([]Player, error) {
+	res, err := s.api.CallContext(ctx, "GET", "players/list", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Players []Player `json:"players"`
+	}
+	err = decodeResponse(res, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Players, nil
+}
+
+// Update changes the non-empty settings of the player with the given ID and returns the updated player.
+func (s *PlayerService) Update(ctx context.Context, id string, player PlayerOptions, opts ...CallOption) (*Player, error) {
+	res, err := s.api.CallContext(ctx, "PUT", "players/update/"+id, player.values(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodePlayer(res)
+}
+
+// Delete deletes the player with the given ID.
+func (s *PlayerService) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	_, err := s.api.CallContext(ctx, "DELETE", "players/delete/"+id, nil, opts...)
+	return err
+}
+
+func decodePlayer(res Response) (*Player, error) {
+	var player Player
+	err := decodeResponse(res, &player)
+	if err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// boolValue formats b as Publitio expects flags, "1" or "0".
+func boolValue(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
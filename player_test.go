@@ -0,0 +1,73 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestPlayerService(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "id": "p1", "name": "main", "skin": "dark", "color": "#ff0000", "auto_play": 1}`
+	server.responses = map[string]string{
+		"/v1/players/list": `{"success": true, "players": [{"id": "p1", "name": "main"}, {"id": "p2", "name": "ads"}]}`,
+	}
+	api := API{Key: testKey, Secret: testSecret}
+	players := api.Players()
+	ctx := context.Background()
+
+	autoPlay := true
+	player, err := players.Create(ctx, PlayerOptions{
+		Name:            "main",
+		Skin:            "dark",
+		Color:           "#ff0000",
+		BackgroundColor: "#000000",
+		AutoPlay:        &autoPlay,
+		Values:          url.Values{"extra": {"1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.ID != "p1" || player.Skin != "dark" || player.Color != "#ff0000" || player.AutoPlay != 1 {
+		t.Errorf("got %+v, want the created player", player)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/players/create" {
+		t.Errorf("got %s %s, want POST /v1/players/create", req.Method, req.Path)
+	}
+	want := url.Values{"name": {"main"}, "skin": {"dark"}, "color": {"#ff0000"}, "background_color": {"#000000"}, "auto_play": {"1"}, "extra": {"1"}}
+	for key := range want {
+		if req.Query.Get(key) != want.Get(key) {
+			t.Errorf("got %s=%q, want %q", key, req.Query.Get(key), want.Get(key))
+		}
+	}
+	if req.Query.Has("logo_url") {
+		t.Errorf("got empty setting logo_url in %v", req.Query)
+	}
+
+	autoPlay = false
+	_, err = players.Update(ctx, "p1", PlayerOptions{AutoPlay: &autoPlay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = server.lastRequest(t)
+	if req.Method != "PUT" || req.Path != "/v1/players/update/p1" || req.Query.Get("auto_play") != "0" || req.Query.Has("name") {
+		t.Errorf("got %s %s?%v, want PUT /v1/players/update/p1 with only auto_play=0", req.Method, req.Path, req.Query)
+	}
+
+	list, err := players.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].ID != "p2" {
+		t.Errorf("got %+v, want two players", list)
+	}
+
+	err = players.Delete(ctx, "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "DELETE" || req.Path != "/v1/players/delete/p1" {
+		t.Errorf("got %s %s, want DELETE /v1/players/delete/p1", req.Method, req.Path)
+	}
+}
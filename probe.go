@@ -0,0 +1,91 @@
+package publitio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DeliveryURL is the default Publitio media delivery endpoint probed by ProbeDelivery.
+const DeliveryURL = "https://media.publit.io"
+
+// ProbeStats summarizes the latency of repeated requests to a single URL.
+// Latency is measured until the response headers are received.
+type ProbeStats struct {
+	URL      string
+	Samples  int   // Number of successful requests
+	Failures int   // Number of failed requests
+	LastErr  error // Error of the last failed request, if any
+	Min      time.Duration
+	Max      time.Duration
+	Mean     time.Duration
+}
+
+// probeTimeout limits each request made by ProbeDelivery.
+const probeTimeout = 30 * time.Second
+
+// ProbeDelivery sends samples HEAD requests to each of the given URLs, one at a time, through
+// the HTTP client of api, and reports the latency observed from the current host. Any HTTP
+// response counts as a successful sample, regardless of its status code. Each request may
+// take up to 30 seconds; once ctx is done, no more requests are made and the statistics
+// gathered so far are returned. If no URLs are given, DeliveryURL is probed.
+func (api *API) ProbeDelivery(ctx context.Context, samples int, urls ...string) []ProbeStats {
+	if len(urls) == 0 {
+		urls = []string{DeliveryURL}
+	}
+
+	stats := make([]ProbeStats, len(urls))
+	for i, u := range urls {
+		stats[i] = api.probe(ctx, u, samples)
+	}
+	return stats
+}
+
+func (api *API) probe(ctx context.Context, u string, samples int) ProbeStats {
+	stats := ProbeStats{URL: u}
+	var total time.Duration
+
+	for i := 0; i < samples && ctx.Err() == nil; i++ {
+		elapsed, err := api.probeOnce(ctx, u)
+		if err != nil {
+			stats.Failures++
+			stats.LastErr = fmt.Errorf("error while probing %s: %v", u, err)
+			continue
+		}
+
+		if stats.Samples == 0 || elapsed < stats.Min {
+			stats.Min = elapsed
+		}
+		if elapsed > stats.Max {
+			stats.Max = elapsed
+		}
+		total += elapsed
+		stats.Samples++
+	}
+
+	if stats.Samples > 0 {
+		stats.Mean = total / time.Duration(stats.Samples)
+	}
+	return stats
+}
+
+// probeOnce sends a single HEAD request to u and returns the time until its response headers.
+func (api *API) probeOnce(ctx context.Context, u string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "HEAD", u, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", api.UserAgentString())
+
+	start := time.Now()
+	res, err := api.httpClient().Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+	return elapsed, nil
+}
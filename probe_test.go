@@ -0,0 +1,61 @@
+package publitio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbeDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("got method %s, want HEAD", r.Method)
+		}
+		if r.Header.Get("User-Agent") != DefaultUserAgent {
+			t.Errorf("got User-Agent %q, want %q", r.Header.Get("User-Agent"), DefaultUserAgent)
+		}
+	}))
+	defer server.Close()
+
+	api := API{Key: testKey, Secret: testSecret}
+	stats := api.ProbeDelivery(context.Background(), 3, server.URL, "http://127.0.0.1:1")
+	if len(stats) != 2 {
+		t.Fatalf("got %d results, want 2", len(stats))
+	}
+
+	ok := stats[0]
+	if ok.Samples != 3 || ok.Failures != 0 {
+		t.Errorf("got %d samples and %d failures, want 3 and 0", ok.Samples, ok.Failures)
+	}
+	if ok.Min > ok.Mean || ok.Mean > ok.Max || ok.Max == 0 {
+		t.Errorf("inconsistent latencies: min %v, mean %v, max %v", ok.Min, ok.Mean, ok.Max)
+	}
+
+	failed := stats[1]
+	if failed.Samples != 0 || failed.Failures != 3 || failed.LastErr == nil {
+		t.Errorf("got %d samples, %d failures and error %v, want 0, 3 and an error", failed.Samples, failed.Failures, failed.LastErr)
+	}
+}
+
+func TestProbeDeliveryClientAndContext(t *testing.T) {
+	var requests int
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})}
+	api := API{Key: testKey, Secret: testSecret, HTTPClient: client}
+
+	stats := api.ProbeDelivery(context.Background(), 2, "https://media.example.org")
+	if requests != 2 || stats[0].Samples != 2 {
+		t.Errorf("got %d requests through the client and %+v, want 2 samples", requests, stats[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	requests = 0
+	stats = api.ProbeDelivery(ctx, 5, "https://media.example.org")
+	if requests != 0 || stats[0].Samples != 0 {
+		t.Errorf("got %d requests and %+v after cancellation, want none", requests, stats[0])
+	}
+}
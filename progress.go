@@ -0,0 +1,135 @@
+package publitio
+
+import (
+	"io"
+	"sync"
+)
+
+// UploadProgress is a snapshot of the progress of an upload.
+type UploadProgress struct {
+	Sent  int64 // Number of bytes sent so far
+	Total int64 // Total number of bytes, -1 if unknown
+}
+
+// ProgressReader wraps a reader and reports the number of bytes read through it.
+// Uploading a ProgressReader reports the progress of that upload alone, unlike
+// API.Progress, which reports every upload.
+type ProgressReader struct {
+	r        io.Reader
+	total    int64
+	read     int64
+	progress ProgressFunc
+}
+
+// NewProgressReader returns a reader that reads from r and calls progress after every read
+// with the number of bytes read so far. If total is negative, it is determined from r
+// if possible, as for uploads, and is -1 otherwise.
+func NewProgressReader(r io.Reader, total int64, progress ProgressFunc) *ProgressReader {
+	if total < 0 {
+		total = readerSize(r)
+	}
+	return &ProgressReader{r: r, total: total, progress: progress}
+}
+
+// Read reads from the underlying reader and reports the progress.
+func (r *ProgressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.progress(r.read, r.total)
+	}
+	return n, err
+}
+
+// Len returns the number of bytes left to read, or -1 if unknown.
+// It lets uploads send a Content-Length header.
+func (r *ProgressReader) Len() int {
+	if r.total < 0 {
+		return -1
+	}
+	return int(r.total - r.read)
+}
+
+// Name returns the name of the underlying reader, such as an *os.File, if it has one,
+// so that uploads keep the filename.
+func (r *ProgressReader) Name() string {
+	if named, ok := r.r.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	if named, ok := r.r.(*namedFile); ok {
+		return named.name
+	}
+	return ""
+}
+
+// ProgressChannel wraps r in a ProgressReader that sends its progress on the returned
+// channel, so it can be consumed from another goroutine. The channel is closed once r
+// returns an error, including io.EOF, or once the upload of the returned reader returns,
+// even if it failed before reading all of r. Sends never block reading: if the consumer
+// falls behind, older updates are dropped in favour of the latest one.
+func ProgressChannel(r io.Reader, total int64) (io.Reader, <-chan UploadProgress) {
+	updates := make(chan UploadProgress, 1)
+	reader := &progressChannelReader{updates: updates}
+	reader.ProgressReader = NewProgressReader(r, total, reader.send)
+	return reader, updates
+}
+
+type progressChannelReader struct {
+	*ProgressReader
+	updates chan UploadProgress
+
+	mu     sync.Mutex // The HTTP transport may still read the body after the upload returned
+	closed bool
+}
+
+func (r *progressChannelReader) Read(p []byte) (int, error) {
+	n, err := r.ProgressReader.Read(p)
+	if err != nil {
+		r.finish()
+	}
+	return n, err
+}
+
+// finish closes the channel, if it isn't closed yet.
+func (r *progressChannelReader) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		close(r.updates)
+	}
+}
+
+func (r *progressChannelReader) send(sent, total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	update := UploadProgress{Sent: sent, Total: total}
+	for {
+		select {
+		case r.updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-r.updates: // Drop the stale update
+		default:
+		}
+	}
+}
+
+// finishProgress closes the progress channel of an uploaded file that was created by
+// ProgressChannel, possibly wrapped by NamedFile or NewProgressReader, once the upload
+// has returned.
+func finishProgress(file io.Reader) {
+	switch f := file.(type) {
+	case *progressChannelReader:
+		f.finish()
+	case *namedFile:
+		finishProgress(f.Reader)
+	case *ProgressReader:
+		finishProgress(f.r)
+	}
+}
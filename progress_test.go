@@ -0,0 +1,94 @@
+package publitio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProgressReader(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 10000)
+	var sent, total int64
+	reader := NewProgressReader(bytes.NewReader(content), -1, func(s, t int64) { sent, total = s, t })
+
+	if reader.Len() != len(content) {
+		t.Errorf("got Len %d, want %d", reader.Len(), len(content))
+	}
+	if _, err := io.Copy(ioutil.Discard, reader); err != nil {
+		t.Fatal(err)
+	}
+	if sent != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("got %d of %d, want %d of %d", sent, total, len(content), len(content))
+	}
+	if reader.Len() != 0 {
+		t.Errorf("got Len %d after reading everything, want 0", reader.Len())
+	}
+}
+
+func TestProgressChannel(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+	content := bytes.Repeat([]byte("x"), 1<<20)
+
+	reader, updates := ProgressChannel(NamedFile(bytes.NewReader(content), "big.bin", ""), int64(len(content)))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := api.UploadFile(reader, nil)
+		errc <- err
+	}()
+
+	var last UploadProgress
+	for update := range updates {
+		if update.Sent < last.Sent {
+			t.Errorf("progress went back from %d to %d", last.Sent, update.Sent)
+		}
+		last = update
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if last.Sent != int64(len(content)) || last.Total != int64(len(content)) {
+		t.Errorf("got final progress %+v, want %d of %d", last, len(content), len(content))
+	}
+	req := server.lastRequest(t)
+	if !bytes.Contains(req.Body, []byte(`filename="big.bin"`)) {
+		t.Error("the filename was lost")
+	}
+	if req.ContentLength != int64(len(req.Body)) {
+		t.Errorf("got Content-Length %d, want %d", req.ContentLength, len(req.Body))
+	}
+}
+
+func TestProgressChannelClosedOnFailure(t *testing.T) {
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.Body.Close()
+		return nil, errors.New("connection refused")
+	})}
+
+	for name, api := range map[string]*API{
+		"read-only":         {Key: testKey, Secret: testSecret, ReadOnly: true},
+		"connection failed": {Key: testKey, Secret: testSecret, HTTPClient: client},
+	} {
+		reader, updates := ProgressChannel(bytes.NewReader(bytes.Repeat([]byte("x"), 1<<20)), -1)
+		if _, err := api.UploadFile(reader, nil); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			for range updates {
+			}
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Errorf("%s: the channel wasn't closed", name)
+		}
+	}
+}
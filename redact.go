@@ -0,0 +1,76 @@
+package publitio
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redactedParams are the signing parameters hidden by RedactURL.
+var redactedParams = []string{"api_key", "api_signature"}
+
+// Redactor rewrites a signed request URL so it can be shown in errors and logs.
+type Redactor func(u string) string
+
+// RedactURL replaces the values of the api_key and api_signature query parameters in u
+// with "REDACTED". If u can't be parsed, its whole query is dropped.
+func RedactURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		if i := strings.IndexByte(u, '?'); i >= 0 {
+			return u[:i] + "?REDACTED"
+		}
+		return u
+	}
+	query := parsed.Query()
+	for _, param := range redactedParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, "REDACTED")
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func (api *API) redact(u string) string {
+	if api.Redact != nil {
+		return api.Redact(u)
+	}
+	return RedactURL(u)
+}
+
+// redactError redacts the request URL carried by err, if any.
+func (api *API) redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = api.redact(urlErr.URL)
+	}
+	return err
+}
+
+// logCurl writes a curl command equivalent to a request to api.CurlLog, if it is set.
+// A non-empty fileName adds the multipart file field.
+func (api *API) logCurl(method, u, fileName string) {
+	if api.CurlLog == nil {
+		return
+	}
+	if !api.CurlSecrets {
+		u = api.redact(u)
+	}
+
+	command := "curl -X " + method
+	if method == "POST" {
+		if fileName != "" {
+			command += " -F " + shellQuote("file=@"+fileName)
+		} else {
+			command += " -H " + shellQuote("Content-Type: multipart/form-data")
+		}
+	}
+	fmt.Fprintln(api.CurlLog, command+" "+shellQuote(u))
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
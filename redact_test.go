@@ -0,0 +1,95 @@
+package publitio
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCurlLog(t *testing.T) {
+	newFakeServer(t)
+	var log bytes.Buffer
+	api := API{Key: testKey, Secret: testSecret, CurlLog: &log}
+
+	api.Put("files/update/fileId", url.Values{"title": {"It's mine"}})
+	api.UploadFile(strings.NewReader("content"), nil)
+	api.UploadFile(nil, url.Values{"file_url": {"https://example.org/file.png"}})
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d commands, want 3:\n%s", len(lines), log.String())
+	}
+	prefixes := []string{
+		"curl -X PUT 'https://api.publit.io/v1/files/update/fileId?",
+		"curl -X POST -F 'file=@file' 'https://api.publit.io/v1/files/create?",
+		"curl -X POST -H 'Content-Type: multipart/form-data' 'https://api.publit.io/v1/files/create?",
+	}
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("got command %q, want prefix %q", lines[i], prefix)
+		}
+		if !strings.Contains(lines[i], "api_key=REDACTED") || !strings.Contains(lines[i], "api_signature=REDACTED") {
+			t.Errorf("command %q is not redacted", lines[i])
+		}
+		if strings.Contains(lines[i], "api_key="+testKey) {
+			t.Errorf("command %q contains the API key", lines[i])
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellQuote("it's"), `'it'\''s'`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCurlLogSecrets(t *testing.T) {
+	newFakeServer(t)
+	var log bytes.Buffer
+	api := API{Key: testKey, Secret: testSecret, CurlLog: &log, CurlSecrets: true}
+
+	api.Get("files/list", nil)
+	if !strings.Contains(log.String(), "api_key="+testKey) {
+		t.Errorf("command %q does not contain the API key", log.String())
+	}
+}
+
+func TestErrorsAreRedacted(t *testing.T) {
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	defer func() { http.DefaultTransport = transport }()
+
+	api := API{Key: "secret-api-key", Secret: "secret-api-secret"}
+	_, callErr := api.Get("files/list", nil)
+	_, uploadErr := api.UploadFile(strings.NewReader("content"), nil)
+	_, urlUploadErr := api.UploadFile(nil, url.Values{"file_url": {"https://example.org"}})
+
+	for _, err := range []error{callErr, uploadErr, urlUploadErr} {
+		if err == nil {
+			t.Fatal("got no error")
+		}
+		if strings.Contains(err.Error(), "secret-api") {
+			t.Errorf("error %q leaks credentials", err)
+		}
+		if !strings.Contains(err.Error(), "api_key=REDACTED") || !strings.Contains(err.Error(), "api_signature=REDACTED") {
+			t.Errorf("error %q has no redacted URL", err)
+		}
+	}
+}
+
+func TestCustomRedactor(t *testing.T) {
+	newFakeServer(t)
+	var log bytes.Buffer
+	api := API{Key: testKey, Secret: testSecret, CurlLog: &log}
+	api.Redact = func(u string) string { return "<hidden>" }
+
+	api.Get("files/list", nil)
+	if got := strings.TrimSpace(log.String()); got != "curl -X GET '<hidden>'" {
+		t.Errorf("got command %q, want the custom redaction", got)
+	}
+}
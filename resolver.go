@@ -0,0 +1,192 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFolderNotFound is returned when a folder path doesn't name an existing folder.
+var ErrFolderNotFound = errors.New("publitio: folder not found")
+
+// FolderResolver resolves slash-separated folder paths, such as "marketing/2025", to folder IDs.
+// It caches the folder tree, so resolving many paths costs a single folders/tree call.
+// It is safe for concurrent use.
+type FolderResolver struct {
+	// TTL is how long the cached folder tree is used. If zero, it is used until Invalidate
+	// is called or a lookup misses.
+	TTL time.Duration
+
+	api *API
+
+	mu     sync.Mutex
+	ids    map[string]string // Folder IDs by path
+	loaded time.Time
+}
+
+// NewFolderResolver returns a FolderResolver that looks up folders through api.
+func NewFolderResolver(api *API) *FolderResolver {
+	return &FolderResolver{api: api}
+}
+
+// Resolve returns the ID of the folder at folderPath, or an error wrapping ErrFolderNotFound if
+// there is none. The empty path and "/" name the top level, whose ID is empty.
+func (r *FolderResolver) Resolve(ctx context.Context, folderPath string) (string, error) {
+	folderPath = cleanFolderPath(folderPath)
+	if folderPath == "" {
+		return "", nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.cached(folderPath); ok {
+		return id, nil
+	}
+	if err := r.load(ctx); err != nil {
+		return "", err
+	}
+	if id, ok := r.ids[folderPath]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrFolderNotFound, folderPath)
+}
+
+// Ensure is like Resolve, but creates the folder at folderPath and any missing parent folders.
+func (r *FolderResolver) Ensure(ctx context.Context, folderPath string) (string, error) {
+	folderPath = cleanFolderPath(folderPath)
+	if folderPath == "" {
+		return "", nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.cached(folderPath); ok {
+		return id, nil
+	}
+	if err := r.load(ctx); err != nil {
+		return "", err
+	}
+
+	var parentID, current string
+	for _, name := range strings.Split(folderPath, "/") {
+		current = path.Join(current, name)
+		if id, ok := r.ids[current]; ok {
+			parentID = id
+			continue
+		}
+		folder, err := r.api.Folders().create(ctx, name, parentID, nil)
+		if err != nil {
+			return "", fmt.Errorf("error while creating folder %s: %w", current, err)
+		}
+		r.ids[current] = folder.ID
+		parentID = folder.ID
+	}
+	return parentID, nil
+}
+
+// subtree returns the IDs of the folder at folderPath and all of its subfolders, sorted by
+// path. The path must not be empty.
+func (r *FolderResolver) subtree(ctx context.Context, folderPath string) ([]string, error) {
+	folderPath = cleanFolderPath(folderPath)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.cached(folderPath); !ok {
+		if err := r.load(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := r.ids[folderPath]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFolderNotFound, folderPath)
+	}
+
+	var paths []string
+	for p := range r.ids {
+		if p == folderPath || strings.HasPrefix(p, folderPath+"/") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var ids []string
+	for _, p := range paths {
+		ids = append(ids, r.ids[p])
+	}
+	return ids, nil
+}
+
+// Invalidate drops the cached folder tree, so the next lookup lists the folders again.
+// FolderService calls it on API.FolderResolver whenever it modifies a folder.
+func (r *FolderResolver) Invalidate() {
+	r.mu.Lock()
+	r.ids = nil
+	r.mu.Unlock()
+}
+
+// cached returns the cached ID of the folder at folderPath, if the cache hasn't expired.
+func (r *FolderResolver) cached(folderPath string) (string, bool) {
+	if r.TTL > 0 && time.Since(r.loaded) > r.TTL {
+		r.ids = nil
+	}
+	id, ok := r.ids[folderPath]
+	return id, ok
+}
+
+// load replaces the cached IDs with those of the current folder tree.
+func (r *FolderResolver) load(ctx context.Context) error {
+	tree, err := r.api.Folders().Tree(ctx)
+	if err != nil {
+		return fmt.Errorf("error while listing folders: %w", err)
+	}
+	r.ids = make(map[string]string)
+	r.loaded = time.Now()
+	r.add("", tree)
+	return nil
+}
+
+func (r *FolderResolver) add(parent string, folders []Folder) {
+	for _, folder := range folders {
+		folderPath := path.Join(parent, folder.Name)
+		r.ids[folderPath] = folder.ID
+		r.add(folderPath, folder.Children)
+	}
+}
+
+// EnsureFolderPath returns the ID of the folder at the slash-separated folderPath,
+// creating it and any missing parent folders. Lookups are cached by api.FolderResolver,
+// if set.
+func (api *API) EnsureFolderPath(ctx context.Context, folderPath string) (string, error) {
+	return api.folderResolver().Ensure(ctx, folderPath)
+}
+
+// folderResolver returns api.FolderResolver, or an uncached resolver if it's nil.
+func (api *API) folderResolver() *FolderResolver {
+	if api.FolderResolver != nil {
+		return api.FolderResolver
+	}
+	return NewFolderResolver(api)
+}
+
+// withFolderResolver returns api if it has a FolderResolver, or otherwise a copy of api with
+// a new one, so that the many folder lookups of a bulk upload share a cache and concurrent
+// uploads into the same missing folder create it only once.
+func (api *API) withFolderResolver() *API {
+	if api.FolderResolver != nil {
+		return api
+	}
+	child := *api
+	child.FolderResolver = NewFolderResolver(&child)
+	return &child
+}
+
+func cleanFolderPath(folderPath string) string {
+	return strings.Trim(path.Clean("/"+folderPath), "/")
+}
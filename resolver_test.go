@@ -0,0 +1,195 @@
+package publitio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// folderResponder serves testTreeJSON and creates folders with the ID "new-<name>".
+func folderResponder(req recordedRequest) string {
+	switch req.Path {
+	case "/v1/folders/tree":
+		return testTreeJSON
+	case "/v1/folders/create":
+		name := req.Query.Get("name")
+		return fmt.Sprintf(`{"success": true, "id": "new-%s", "name": %q, "parent_id": %q}`, name, name, req.Query.Get("parent_id"))
+	}
+	return `{"success": true}`
+}
+
+// growingFolderResponder is like folderResponder, but adds the folders it creates to the
+// tree it serves, as the Publitio server does.
+func growingFolderResponder(t *testing.T) func(req recordedRequest) string {
+	var tree struct {
+		Folders []Folder `json:"folders"`
+	}
+	if err := json.Unmarshal([]byte(testTreeJSON), &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	var insert func(folders []Folder, folder Folder) []Folder
+	insert = func(folders []Folder, folder Folder) []Folder {
+		if folder.ParentID == "" {
+			return append(folders, folder)
+		}
+		for i := range folders {
+			if folders[i].ID == folder.ParentID {
+				folders[i].Children = append(folders[i].Children, folder)
+			} else {
+				folders[i].Children = insert(folders[i].Children, folder)
+			}
+		}
+		return folders
+	}
+
+	return func(req recordedRequest) string {
+		switch req.Path {
+		case "/v1/folders/tree":
+			data, err := json.Marshal(tree.Folders)
+			if err != nil {
+				t.Error(err)
+			}
+			return fmt.Sprintf(`{"success": true, "folders": %s}`, data)
+		case "/v1/folders/create":
+			folder := Folder{ID: "new-" + req.Query.Get("name"), Name: req.Query.Get("name"), ParentID: req.Query.Get("parent_id")}
+			tree.Folders = insert(tree.Folders, folder)
+		}
+		return folderResponder(req)
+	}
+}
+
+func TestFolderResolverResolve(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderResponder
+	api := API{Key: testKey, Secret: testSecret}
+	resolver := NewFolderResolver(&api)
+	ctx := context.Background()
+
+	for folderPath, want := range map[string]string{"marketing/2025": "f2", "/docs/": "f3", "": ""} {
+		id, err := resolver.Resolve(ctx, folderPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != want {
+			t.Errorf("got ID %q for %q, want %q", id, folderPath, want)
+		}
+	}
+	if n := server.countRequests("/v1/folders/tree"); n != 1 {
+		t.Errorf("got %d tree requests, want 1", n)
+	}
+
+	_, err := resolver.Resolve(ctx, "marketing/2026")
+	if !errors.Is(err, ErrFolderNotFound) {
+		t.Errorf("got error %v, want ErrFolderNotFound", err)
+	}
+}
+
+func TestUploadWithFolderPath(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderResponder
+	api := API{Key: testKey, Secret: testSecret}
+	api.FolderResolver = NewFolderResolver(&api)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := api.UploadFileContext(ctx, strings.NewReader("content"), nil, WithFolderPath("marketing/2025/q1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := server.lastRequest(t).Query.Get("folder"); got != "new-q1" {
+			t.Errorf("got folder %q, want new-q1", got)
+		}
+	}
+
+	if n := server.countRequests("/v1/folders/tree"); n != 1 {
+		t.Errorf("got %d tree requests, want 1", n)
+	}
+	if n := server.countRequests("/v1/folders/create"); n != 1 {
+		t.Errorf("got %d create requests, want 1", n)
+	}
+	for _, req := range server.requests {
+		if req.Path == "/v1/folders/create" && req.Query.Get("parent_id") != "f2" {
+			t.Errorf("got parent_id %q, want f2", req.Query.Get("parent_id"))
+		}
+	}
+}
+
+func TestFolderResolverInvalidation(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderResponder
+	api := API{Key: testKey, Secret: testSecret}
+	api.FolderResolver = NewFolderResolver(&api)
+	ctx := context.Background()
+
+	resolve := func() {
+		t.Helper()
+		if _, err := api.FolderResolver.Resolve(ctx, "docs"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resolve()
+	resolve()
+	if _, err := api.Folders().Update(ctx, "f3", url.Values{"name": {"documents"}}); err != nil {
+		t.Fatal(err)
+	}
+	resolve()
+	if n := server.countRequests("/v1/folders/tree"); n != 2 {
+		t.Errorf("got %d tree requests, want 2 after a folder update", n)
+	}
+
+	api.FolderResolver.TTL = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	resolve()
+	if n := server.countRequests("/v1/folders/tree"); n != 3 {
+		t.Errorf("got %d tree requests, want 3 after the cache expired", n)
+	}
+}
+
+func TestUploadWithFolderPathVetoed(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderResponder
+	denied := errors.New("denied")
+	api := API{Key: testKey, Secret: testSecret, Policy: func(method, path string, values url.Values) error {
+		if path == "files/create" {
+			return denied
+		}
+		return nil
+	}}
+
+	_, err := api.UploadFileContext(context.Background(), strings.NewReader("content"), nil, WithFolderPath("marketing/2025/q1"))
+	if !errors.Is(err, denied) {
+		t.Errorf("got error %v, want the policy veto", err)
+	}
+	if len(server.requests) != 0 {
+		t.Errorf("got %d requests, want none for a vetoed upload", len(server.requests))
+	}
+}
+
+func TestListWithFolderPath(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = folderResponder
+	api := API{Key: testKey, Secret: testSecret}
+	ctx := context.Background()
+
+	if _, err := api.Files().List(ctx, nil, WithFolderPath("marketing/2025")); err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Path != "/v1/files/list" || req.Query.Get("folder") != "f2" {
+		t.Errorf("got %s?%v, want a listing of folder f2", req.Path, req.Query)
+	}
+
+	_, err := api.Files().List(ctx, nil, WithFolderPath("marketing/2026"))
+	if !errors.Is(err, ErrFolderNotFound) {
+		t.Errorf("got error %v, want ErrFolderNotFound", err)
+	}
+	if n := server.countRequests("/v1/folders/create"); n != 0 {
+		t.Errorf("got %d create requests, want none", n)
+	}
+}
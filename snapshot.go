@@ -0,0 +1,137 @@
+package publitio
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// snapshotPageSize is the number of files requested per page while taking a snapshot.
+const snapshotPageSize = 100
+
+// SnapshotDiff lists the IDs of files that differ between two snapshots, each sorted.
+type SnapshotDiff struct {
+	Added   []string // Files only in the new snapshot
+	Removed []string // Files only in the old snapshot
+	Changed []string // Files in both snapshots whose records differ, apart from their URLs
+}
+
+// SnapshotInventory lists every file and writes the file records to w as newline-delimited
+// JSON, one file per line, returning the number of files written. Defaults for
+// "files/list" apply, so a scoped API snapshots only its folder.
+func (api *API) SnapshotInventory(w io.Writer) (int, error) {
+	// The records are listed as they are, rather than as Files, to keep every field
+	next := listPages(url.Values{"limit": {strconv.Itoa(snapshotPageSize)}}, api.listFiles)
+	encoder := json.NewEncoder(w)
+	count := 0
+	for {
+		file, err := next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		err = encoder.Encode(file)
+		if err != nil {
+			return count, fmt.Errorf("error while writing snapshot: %v", err)
+		}
+		count++
+	}
+}
+
+// DiffSnapshots compares two snapshots written by SnapshotInventory. The url_ fields of the
+// records are ignored, since signed URLs change on every listing.
+func DiffSnapshots(old, new io.Reader) (SnapshotDiff, error) {
+	oldFiles, err := readSnapshot(old)
+	if err != nil {
+		return SnapshotDiff{}, fmt.Errorf("error while reading old snapshot: %v", err)
+	}
+	newFiles, err := readSnapshot(new)
+	if err != nil {
+		return SnapshotDiff{}, fmt.Errorf("error while reading new snapshot: %v", err)
+	}
+
+	var diff SnapshotDiff
+	for id, newFile := range newFiles {
+		oldFile, ok := oldFiles[id]
+		if !ok {
+			diff.Added = append(diff.Added, id)
+		} else if !reflect.DeepEqual(withoutURLs(oldFile), withoutURLs(newFile)) {
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range oldFiles {
+		if _, ok := newFiles[id]; !ok {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// withoutURLs returns a copy of a file record without its url_ fields.
+func withoutURLs(file map[string]interface{}) map[string]interface{} {
+	stable := make(map[string]interface{}, len(file))
+	for key, value := range file {
+		if !strings.HasPrefix(key, "url_") {
+			stable[key] = value
+		}
+	}
+	return stable
+}
+
+func readSnapshot(r io.Reader) (map[string]map[string]interface{}, error) {
+	files := make(map[string]map[string]interface{})
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var file map[string]interface{}
+		err := json.Unmarshal(scanner.Bytes(), &file)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing line %d: %v", line, err)
+		}
+		id, _ := file["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("line %d has no file ID", line)
+		}
+		files[id] = file
+	}
+	return files, scanner.Err()
+}
+
+// listFiles returns a single page of file records, as given by the offset and limit values.
+func (api *API) listFiles(values url.Values) ([]map[string]interface{}, error) {
+	res, err := api.Get("files/list", values)
+	if err != nil {
+		return nil, err
+	}
+
+	object, _ := res.(map[string]interface{})
+	list, ok := object["files"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no file list in response %v", res)
+	}
+
+	files := make([]map[string]interface{}, 0, len(list))
+	for _, f := range list {
+		file, ok := f.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected file record %v", f)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
@@ -0,0 +1,110 @@
+package publitio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fileListResponder answers files/list requests with the given file records, honoring
+// offset and limit.
+func fileListResponder(files []map[string]interface{}) func(req recordedRequest) string {
+	return func(req recordedRequest) string {
+		offset, _ := strconv.Atoi(req.Query.Get("offset"))
+		limit, _ := strconv.Atoi(req.Query.Get("limit"))
+		if offset > len(files) {
+			offset = len(files)
+		}
+		end := offset + limit
+		if limit == 0 || end > len(files) {
+			end = len(files)
+		}
+		page, _ := json.Marshal(map[string]interface{}{
+			"success":     true,
+			"files_count": len(files),
+			"files":       files[offset:end],
+		})
+		return string(page)
+	}
+}
+
+func testFiles(n int) []map[string]interface{} {
+	files := make([]map[string]interface{}, n)
+	for i := range files {
+		files[i] = map[string]interface{}{"id": fmt.Sprintf("id%03d", i), "title": fmt.Sprintf("File %d", i)}
+	}
+	return files
+}
+
+func TestSnapshotInventory(t *testing.T) {
+	server := newFakeServer(t)
+	files := testFiles(2*snapshotPageSize + 50)
+	server.respond = fileListResponder(files)
+	api := API{Key: testKey, Secret: testSecret}
+
+	var snapshot bytes.Buffer
+	count, err := api.SnapshotInventory(&snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(files) {
+		t.Errorf("got %d files, want %d", count, len(files))
+	}
+	if lines := strings.Count(snapshot.String(), "\n"); lines != len(files) {
+		t.Errorf("got %d lines, want %d", lines, len(files))
+	}
+	if len(server.requests) != 4 {
+		t.Errorf("got %d requests, want 3 pages and an empty one", len(server.requests))
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	files := testFiles(5)
+	server.respond = fileListResponder(files)
+	var old bytes.Buffer
+	if _, err := api.SnapshotInventory(&old); err != nil {
+		t.Fatal(err)
+	}
+
+	files = append(files[1:], map[string]interface{}{"id": "new", "title": "New"})
+	files[1] = map[string]interface{}{"id": files[1]["id"], "title": "Renamed"}
+	server.respond = fileListResponder(files)
+	var new bytes.Buffer
+	if _, err := api.SnapshotInventory(&new); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffSnapshots(&old, &new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SnapshotDiff{Added: []string{"new"}, Removed: []string{"id000"}, Changed: []string{"id002"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDiffSnapshotsIgnoresURLs(t *testing.T) {
+	old := `{"id": "a", "title": "A", "url_download": "https://media.publit.io/download/a.png?at=first"}`
+	new := `{"id": "a", "title": "A", "url_download": "https://media.publit.io/download/a.png?at=second", "url_preview": "https://media.publit.io/file/a.png"}`
+	diff, err := DiffSnapshots(strings.NewReader(old), strings.NewReader(new))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(diff, SnapshotDiff{}) {
+		t.Errorf("got %+v for records differing only in their URLs, want no changes", diff)
+	}
+}
+
+func TestDiffSnapshotsInvalid(t *testing.T) {
+	if _, err := DiffSnapshots(strings.NewReader(`{"title": "no ID"}`), strings.NewReader("")); err == nil {
+		t.Error("got no error for a record without an ID")
+	}
+}
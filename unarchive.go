@@ -0,0 +1,154 @@
+package publitio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// ArchiveMemberResult is the outcome of uploading a single archive member.
+type ArchiveMemberResult struct {
+	Name   string   // Path of the member inside the archive
+	Result Response // Server response, nil if the upload failed
+	Err    error    // Upload error, if any
+}
+
+// UploadArchive expands a zip, tar or gzipped tar archive and uploads every regular file
+// in it individually. Files are uploaded to the folder at the slash-separated folderPath
+// joined with their directory inside the archive, so relative paths are preserved; missing
+// folders are created, see EnsureFolderPath. Files are titled after their base name unless
+// values has a title. The remaining values are passed to every upload.
+//
+// A zip archive is read through io.ReaderAt if archive implements it and its size is known,
+// as for *os.File and *bytes.Reader; the whole content is used then, regardless of how much
+// of it was already read. Other zip archives are buffered in memory, since the zip central
+// directory is at the end of the archive. Tar archives are always streamed.
+//
+// A failed upload doesn't stop the others; its error is reported in the member's result.
+// An error is returned if the archive itself can't be read or ctx is done, together with
+// the results of the members uploaded so far.
+func (api *API) UploadArchive(ctx context.Context, archive io.Reader, folderPath string, values url.Values) ([]ArchiveMemberResult, error) {
+	api = api.withFolderResolver()
+	if readerAt, size, ok := readerAtSize(archive); ok {
+		magic := make([]byte, len(zipMagic))
+		n, _ := readerAt.ReadAt(magic, 0)
+		if bytes.HasPrefix(magic[:n], zipMagic) {
+			return api.uploadZip(ctx, readerAt, size, folderPath, values)
+		}
+	}
+
+	r := bufio.NewReader(archive)
+	magic, _ := r.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(magic, zipMagic):
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading zip archive: %v", err)
+		}
+		return api.uploadZip(ctx, bytes.NewReader(content), int64(len(content)), folderPath, values)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading gzip archive: %v", err)
+		}
+		defer gz.Close()
+		return api.uploadTar(ctx, gz, folderPath, values)
+	default:
+		return api.uploadTar(ctx, r, folderPath, values)
+	}
+}
+
+// zipMagic starts every zip archive that isn't empty.
+var zipMagic = []byte("PK\x03\x04")
+
+// readerAtSize returns r as an io.ReaderAt together with its size, if both are available.
+func readerAtSize(r io.Reader) (io.ReaderAt, int64, bool) {
+	readerAt, ok := r.(io.ReaderAt)
+	if !ok {
+		return nil, 0, false
+	}
+	switch r := r.(type) {
+	case interface{ Size() int64 }:
+		return readerAt, r.Size(), true
+	case interface{ Stat() (os.FileInfo, error) }:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil, 0, false
+		}
+		return readerAt, info.Size(), true
+	}
+	return nil, 0, false
+}
+
+func (api *API) uploadZip(ctx context.Context, archive io.ReaderAt, size int64, folderPath string, values url.Values) ([]ArchiveMemberResult, error) {
+	zipReader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading zip archive: %v", err)
+	}
+
+	var results []ArchiveMemberResult
+	for _, f := range zipReader.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("archive upload interrupted: %w", err)
+		}
+		member, err := f.Open()
+		if err != nil {
+			results = append(results, ArchiveMemberResult{Name: f.Name, Err: fmt.Errorf("error while opening archive member: %v", err)})
+			continue
+		}
+		results = append(results, api.uploadMember(ctx, member, f.Name, folderPath, values))
+		member.Close()
+	}
+	return results, nil
+}
+
+func (api *API) uploadTar(ctx context.Context, archive io.Reader, folderPath string, values url.Values) ([]ArchiveMemberResult, error) {
+	tarReader := tar.NewReader(archive)
+
+	var results []ArchiveMemberResult
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return results, fmt.Errorf("error while reading tar archive: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("archive upload interrupted: %w", err)
+		}
+		results = append(results, api.uploadMember(ctx, tarReader, header.Name, folderPath, values))
+	}
+}
+
+func (api *API) uploadMember(ctx context.Context, member io.Reader, name, folderPath string, values url.Values) ArchiveMemberResult {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	memberValues := copyValues(values)
+	if memberValues.Get("title") == "" {
+		memberValues.Set("title", path.Base(name))
+	}
+
+	var opts []CallOption
+	if memberFolder := cleanFolderPath(path.Join(folderPath, path.Dir(name))); memberFolder != "" {
+		opts = append(opts, WithFolderPath(memberFolder))
+	}
+	result, err := api.UploadFileContext(ctx, member, memberValues, opts...)
+	return ArchiveMemberResult{Name: name, Result: result, Err: err}
+}
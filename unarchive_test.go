@@ -0,0 +1,178 @@
+package publitio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"testing"
+)
+
+var archiveMembers = map[string]string{
+	"top.txt":            "top",
+	"images/a.png":       "a",
+	"images/deep/b.png":  "b",
+	"../escape/evil.txt": "evil",
+}
+
+func zipArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	w.Create("images/")
+	for name, content := range archiveMembers {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	w.WriteHeader(&tar.Header{Name: "images/", Typeflag: tar.TypeDir, Mode: 0755})
+	for name, content := range archiveMembers {
+		if err := w.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(content)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadArchive(t *testing.T) {
+	archives := map[string]func(t *testing.T) []byte{
+		"zip":    zipArchive,
+		"tar":    tarArchive,
+		"tar.gz": func(t *testing.T) []byte { return gzipped(t, tarArchive(t)) },
+	}
+
+	for format, archive := range archives {
+		t.Run(format, func(t *testing.T) {
+			server := newFakeServer(t)
+			server.respond = growingFolderResponder(t)
+			api := API{Key: testKey, Secret: testSecret}
+
+			results, err := api.UploadArchive(context.Background(), bytes.NewReader(archive(t)), "marketing", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(archiveMembers) {
+				t.Fatalf("got %d results, want %d", len(results), len(archiveMembers))
+			}
+			for _, result := range results {
+				if result.Err != nil {
+					t.Errorf("%s: %v", result.Name, result.Err)
+				}
+			}
+
+			var got []string
+			for _, req := range server.requests {
+				switch req.Path {
+				case "/v1/files/create":
+					got = append(got, req.Query.Get("folder")+" "+req.Query.Get("title"))
+				case "/v1/folders/create":
+					got = append(got, req.Query.Get("parent_id")+" > "+req.Query.Get("name"))
+				}
+			}
+			sort.Strings(got)
+			want := []string{
+				"f1 > escape", "f1 > images", "f1 top.txt",
+				"new-deep b.png", "new-escape evil.txt", "new-images > deep", "new-images a.png",
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got requests %q, want %q", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("got request %q, want %q", got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+// unreadable is an io.ReaderAt whose Read always fails.
+type unreadable struct {
+	*bytes.Reader
+}
+
+func (unreadable) Read([]byte) (int, error) {
+	return 0, errors.New("read called")
+}
+
+func TestUploadArchiveZipSources(t *testing.T) {
+	archives := map[string]io.Reader{
+		"reader at":    unreadable{bytes.NewReader(zipArchive(t))},
+		"plain reader": struct{ io.Reader }{bytes.NewReader(zipArchive(t))},
+	}
+
+	for name, archive := range archives {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeServer(t)
+			server.respond = growingFolderResponder(t)
+			api := API{Key: testKey, Secret: testSecret}
+
+			results, err := api.UploadArchive(context.Background(), archive, "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(archiveMembers) {
+				t.Fatalf("got %d results, want %d", len(results), len(archiveMembers))
+			}
+			for _, result := range results {
+				if result.Err != nil {
+					t.Errorf("%s: %v", result.Name, result.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadArchiveCorrupt(t *testing.T) {
+	newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	archive := tarArchive(t)
+	_, err := api.UploadArchive(context.Background(), io.LimitReader(bytes.NewReader(archive), 700), "", nil)
+	if err == nil {
+		t.Error("got no error for a truncated archive")
+	}
+}
+
+func TestUploadArchiveCancelled(t *testing.T) {
+	server := newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := api.UploadArchive(ctx, bytes.NewReader(tarArchive(t)), "", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if len(server.requests) != 0 {
+		t.Errorf("got %d requests, want none", len(server.requests))
+	}
+}
@@ -0,0 +1,153 @@
+package publitio
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// UploadDirOptions configures UploadDir.
+type UploadDirOptions struct {
+	Workers int        // Number of concurrent uploads, 4 if zero
+	Retries int        // Number of times an upload the server didn't store is retried
+	Values  url.Values // Passed to every upload, as for UploadFile
+}
+
+// UploadDirResult is the outcome of uploading a single file of a directory.
+type UploadDirResult struct {
+	Path     string   // Slash-separated path of the file relative to the directory
+	Result   Response // Server response, nil if the upload failed
+	Attempts int      // Number of upload attempts made
+	Err      error    // Error of the last attempt, if the upload failed
+}
+
+const defaultUploadDirWorkers = 4
+
+// UploadDir walks localDir and uploads every regular file in it through a bounded pool
+// of workers. Files are uploaded to the folder at the slash-separated path remoteFolder
+// joined with their directory relative to localDir, so the tree structure is preserved;
+// missing folders are created, see EnsureFolderPath. Files are titled after their name
+// unless opts.Values has a title. Uploads that provably didn't reach the server, because
+// the connection couldn't be established, the call was rate limited or the server answered
+// with an error, are retried up to opts.Retries times. Uploads whose response was lost are
+// not retried, since the file may have been stored; their error is reported instead.
+//
+// A failed upload doesn't stop the others; its error is reported in the file's result.
+// Results are in walk order. An error is returned if the directory can't be walked or ctx
+// is done, together with the results of the files visited so far.
+//
+// If ctx has a deadline, no upload is started once the average upload so far wouldn't
+// finish before it. The uploads in flight complete and a *PartialCompletion listing the
+// remaining files, as paths relative to localDir, is returned with their results.
+func (api *API) UploadDir(ctx context.Context, localDir, remoteFolder string, opts UploadDirOptions) ([]UploadDirResult, error) {
+	api = api.withFolderResolver()
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultUploadDirWorkers
+	}
+
+	var paths []string
+	err := filepath.Walk(localDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while walking %s: %v", localDir, err)
+	}
+
+	results := make([]UploadDirResult, len(paths))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var cost costEstimate
+
+	dispatched := 0
+	partial := false
+dispatch:
+	for i := range paths {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		if !cost.fits(ctx) {
+			partial = true
+			break dispatch
+		}
+
+		dispatched++
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			results[i] = api.uploadDirFile(ctx, localDir, paths[i], remoteFolder, opts)
+			cost.add(time.Since(start))
+		}(i)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results[:dispatched], fmt.Errorf("directory upload interrupted: %w", err)
+	}
+	if partial {
+		remaining := make([]string, 0, len(paths)-dispatched)
+		for _, p := range paths[dispatched:] {
+			relative, err := filepath.Rel(localDir, p)
+			if err != nil {
+				relative = p
+			}
+			remaining = append(remaining, filepath.ToSlash(relative))
+		}
+		return results[:dispatched], &PartialCompletion{Done: dispatched, Remaining: remaining}
+	}
+	return results, nil
+}
+
+func (api *API) uploadDirFile(ctx context.Context, localDir, localPath, remoteFolder string, opts UploadDirOptions) UploadDirResult {
+	relative, err := filepath.Rel(localDir, localPath)
+	if err != nil {
+		return UploadDirResult{Path: localPath, Err: err}
+	}
+	relative = filepath.ToSlash(relative)
+
+	values := copyValues(opts.Values)
+	if values.Get("title") == "" {
+		values.Set("title", path.Base(relative))
+	}
+	var callOpts []CallOption
+	if folder := cleanFolderPath(path.Join(remoteFolder, path.Dir(relative))); folder != "" {
+		callOpts = append(callOpts, WithFolderPath(folder))
+	}
+
+	result := UploadDirResult{Path: relative}
+	backoff := waitInitialInterval
+	for {
+		result.Attempts++
+		result.Result, result.Err = api.UploadFileFromPathContext(ctx, localPath, values, callOpts...)
+		if result.Err == nil || !uploadRetryable(result.Err) || result.Attempts > opts.Retries || ctx.Err() != nil {
+			return result
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return result
+		}
+		backoff *= 2
+		if backoff > waitMaxInterval {
+			backoff = waitMaxInterval
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDir creates a directory tree with the given slash-separated files.
+func testDir(t *testing.T, files ...string) string {
+	dir, err := ioutil.TempDir("", "publitio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte("content of "+f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestUploadDir(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = growingFolderResponder(t)
+	dir := testDir(t, "a.jpg", "img/b.png", "img/deep/c.gif", "video/d.mp4", "e.txt")
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	api := NewAPI(testKey, testSecret, WithHTTPClient(client))
+
+	results, err := api.UploadDir(context.Background(), dir, "site", UploadDirOptions{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5", len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Attempts != 1 {
+			t.Errorf("%s: got %d attempts and error %v", result.Path, result.Attempts, result.Err)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("got %d concurrent uploads, want at most 2", maxInFlight)
+	}
+
+	var got []string
+	for _, req := range server.requests {
+		switch req.Path {
+		case "/v1/files/create":
+			got = append(got, req.Query.Get("folder")+" "+req.Query.Get("title"))
+		case "/v1/folders/create":
+			got = append(got, req.Query.Get("parent_id")+" > "+req.Query.Get("name"))
+		}
+	}
+	sort.Strings(got)
+	want := []string{
+		" > site",
+		"new-deep c.gif", "new-img > deep", "new-img b.png",
+		"new-site > img", "new-site > video", "new-site a.jpg", "new-site e.txt",
+		"new-video d.mp4",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got requests %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got request %q, want %q", got[i], want[i])
+		}
+	}
+}
+
+func TestUploadDirRetries(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		if len(server.requests) == 1 {
+			server.status = http.StatusServiceUnavailable
+			return `{"success": false, "error": {"message": "Try again later"}}`
+		}
+		server.status = 0
+		return `{"success": true}`
+	}
+	dir := testDir(t, "a.jpg")
+	api := API{Key: testKey, Secret: testSecret}
+
+	results, err := api.UploadDir(context.Background(), dir, "", UploadDirOptions{Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Err != nil || results[0].Attempts != 2 {
+		t.Errorf("got %+v, want a successful second attempt", results)
+	}
+	if len(server.requests) != 2 {
+		t.Errorf("got %d requests, want 2", len(server.requests))
+	}
+}
+
+func TestUploadDirCutOffResponse(t *testing.T) {
+	server := newFakeServer(t)
+	server.truncate = 1
+	dir := testDir(t, "a.jpg")
+	api := API{Key: testKey, Secret: testSecret}
+
+	results, err := api.UploadDir(context.Background(), dir, "", UploadDirOptions{Retries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Err == nil || results[0].Attempts != 1 {
+		t.Errorf("got %+v, want a single failed attempt", results)
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("got %d requests, want 1", len(server.requests))
+	}
+}
+
+func TestUploadDirPermanentFailures(t *testing.T) {
+	server := newFakeServer(t)
+	dir := testDir(t, "a.jpg")
+	denied := errors.New("denied")
+
+	tests := map[string]struct {
+		api    API
+		status int
+	}{
+		"read-only":    {api: API{Key: testKey, Secret: testSecret, ReadOnly: true}},
+		"policy":       {api: API{Key: testKey, Secret: testSecret, Policy: func(method, path string, values url.Values) error { return denied }}},
+		"client error": {api: API{Key: testKey, Secret: testSecret}, status: http.StatusBadRequest},
+	}
+	for name, test := range tests {
+		server.status = test.status
+		results, err := test.api.UploadDir(context.Background(), dir, "", UploadDirOptions{Retries: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Err == nil || results[0].Attempts != 1 {
+			t.Errorf("%s: got %+v, want a single failed attempt", name, results)
+		}
+	}
+}
+
+func TestUploadDirCancelled(t *testing.T) {
+	newFakeServer(t)
+	dir := testDir(t, "a.jpg", "b.jpg")
+	api := API{Key: testKey, Secret: testSecret}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := api.UploadDir(ctx, dir, "", UploadDirOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestUploadDirDeadline(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		time.Sleep(100 * time.Millisecond)
+		return `{"success": true}`
+	}
+	api := API{Key: testKey, Secret: testSecret}
+	dir := testDir(t, "0.txt", "1.txt", "2.txt", "3.txt", "4.txt", "5.txt", "6.txt", "7.txt", "8.txt", "9.txt")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 450*time.Millisecond)
+	defer cancel()
+	results, err := api.UploadDir(ctx, dir, "", UploadDirOptions{Workers: 1})
+	var partial *PartialCompletion
+	if !errors.As(err, &partial) {
+		t.Fatalf("got error %v, want a partial completion", err)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("returned after the deadline")
+	}
+	if partial.Done != len(results) || len(results)+len(partial.Remaining) != 10 || len(partial.Remaining) == 0 {
+		t.Fatalf("got %d results and %+v, want the remaining files to complete the directory", len(results), partial)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Path, result.Err)
+		}
+	}
+	if want := "9.txt"; partial.Remaining[len(partial.Remaining)-1] != want {
+		t.Errorf("got remaining %v, want it to end with %s", partial.Remaining, want)
+	}
+}
@@ -0,0 +1,113 @@
+package publitio
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// FileVersion is a transcoded or transformed version of a file.
+type FileVersion struct {
+	ID         string `json:"id"`
+	FileID     string `json:"file_id"`
+	Extension  string `json:"extension"`
+	Options    string `json:"options"`
+	Status     string `json:"status"` // For example "converting" while a video is transcoded, then "ready"
+	Size       int64  `json:"size"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	URLPreview string `json:"url_preview"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// VersionOptions describe a version to create. Empty fields are left out.
+type VersionOptions struct {
+	Format  string     // Extension of the version, for example "webm" or "jpg"
+	Width   int        // Width to resize to, in pixels
+	Height  int        // Height to resize to, in pixels
+	Crop    string     // How to fit the size, for example "fill" or "fit"
+	Quality int        // Quality from 1 to 100
+	Values  url.Values // Any other version parameters, passed along as they are
+}
+
+func (o VersionOptions) values() url.Values {
+	values := copyValues(o.Values)
+	if o.Format != "" {
+		values.Set("extension", o.Format)
+	}
+	if o.Width > 0 {
+		values.Set("w", strconv.Itoa(o.Width))
+	}
+	if o.Height > 0 {
+		values.Set("h", strconv.Itoa(o.Height))
+	}
+	if o.Crop != "" {
+		values.Set("c", o.Crop)
+	}
+	if o.Quality > 0 {
+		values.Set("q", strconv.Itoa(o.Quality))
+	}
+	return values
+}
+
+// VersionService provides typed access to the files/versions endpoints.
+type VersionService struct {
+	api *API
+}
+
+// Versions returns a VersionService that makes its calls through api.
+func (api *API) Versions() *VersionService {
+	return &VersionService{api: api}
+}
+
+// Create creates a version of the file with the given ID and returns it. Video versions are
+// transcoded in the background; poll them with Show until their status is "ready".
+func (s *VersionService) Create(ctx context.Context, fileID string, version VersionOptions, opts ...CallOption) (*FileVersion, error) {
+	res, err := s.api.CallContext(ctx, "POST", "files/versions/create/"+fileID, version.values(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVersion(res)
+}
+
+// Show returns the version with the given ID.
+func (s *VersionService) Show(ctx context.Context, id string, opts ...CallOption) (*FileVersion, error) {
+	res, err := s.api.CallContext(ctx, "GET", "files/versions/show/"+id, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVersion(res)
+}
+
+// List returns the versions of the file with the given ID.
+func (s *VersionService) List(ctx context.Context, fileID string, opts ...CallOption) ([]FileVersion, error) {
+	res, err := s.api.CallContext(ctx, "GET", "files/versions/list/"+fileID, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Versions []FileVersion `json:"versions"`
+	}
+	err = decodeResponse(res, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Versions, nil
+}
+
+// Delete deletes the version with the given ID.
+func (s *VersionService) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	_, err := s.api.CallContext(ctx, "DELETE", "files/versions/delete/"+id, nil, opts...)
+	return err
+}
+
+func decodeVersion(res Response) (*FileVersion, error) {
+	var version FileVersion
+	err := decodeResponse(res, &version)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
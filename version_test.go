@@ -0,0 +1,53 @@
+package publitio
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVersionService(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "id": "v1", "file_id": "fileId", "extension": "webm", "status": "converting"}`
+	server.responses = map[string]string{
+		"/v1/files/versions/list/fileId": `{"success": true, "versions": [{"id": "v1", "status": "ready"}, {"id": "v2", "status": "converting"}]}`,
+	}
+	api := API{Key: testKey, Secret: testSecret}
+	versions := api.Versions()
+	ctx := context.Background()
+
+	version, err := versions.Create(ctx, "fileId", VersionOptions{Format: "webm", Width: 1280, Height: 720, Quality: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.ID != "v1" || version.FileID != "fileId" || version.Status != "converting" {
+		t.Errorf("got %+v, want the created version", version)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/files/versions/create/fileId" {
+		t.Errorf("got %s %s, want POST /v1/files/versions/create/fileId", req.Method, req.Path)
+	}
+	for key, want := range map[string]string{"extension": "webm", "w": "1280", "h": "720", "q": "80"} {
+		if got := req.Query.Get(key); got != want {
+			t.Errorf("got %s=%q, want %q", key, got, want)
+		}
+	}
+	if req.Query.Has("c") {
+		t.Errorf("got empty option c in %v", req.Query)
+	}
+
+	list, err := versions.List(ctx, "fileId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Status != "ready" {
+		t.Errorf("got %+v, want two versions", list)
+	}
+
+	err = versions.Delete(ctx, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "DELETE" || req.Path != "/v1/files/versions/delete/v1" {
+		t.Errorf("got %s %s, want DELETE /v1/files/versions/delete/v1", req.Method, req.Path)
+	}
+}
@@ -0,0 +1,57 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	waitInitialInterval = 250 * time.Millisecond
+	waitMaxInterval     = 5 * time.Second
+)
+
+// WaitUntil polls the file with the given ID until predicate returns true for its record,
+// doubling the interval between polls up to five seconds, and returns the matching record.
+// Use it after creating or updating a file, when reads may briefly return stale data or
+// the file may not be found yet. Not found and transient errors are retried; any other
+// error is returned. If ctx is done first, the last record seen is returned along with
+// the context's error.
+func (api *API) WaitUntil(ctx context.Context, fileID string, predicate func(file Response) bool) (Response, error) {
+	interval := waitInitialInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var file Response
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return file, fmt.Errorf("error while waiting for file %s: %w (last error: %v)", fileID, ctx.Err(), lastErr)
+			}
+			return file, fmt.Errorf("error while waiting for file %s: %w", fileID, ctx.Err())
+		case <-timer.C:
+		}
+
+		res, err := api.CallContext(ctx, "GET", "files/show/"+fileID, nil)
+		switch {
+		case err == nil:
+			file, lastErr = res, nil
+			if predicate(file) {
+				return file, nil
+			}
+		case errors.Is(err, ErrNotFound) || transient(err):
+			lastErr = err
+		default:
+			return file, fmt.Errorf("error while waiting for file %s: %w", fileID, err)
+		}
+
+		timer.Reset(interval)
+		interval *= 2
+		if interval > waitMaxInterval {
+			interval = waitMaxInterval
+		}
+	}
+}
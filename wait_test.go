@@ -0,0 +1,93 @@
+package publitio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWaitUntil(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		if len(server.requests) < 3 {
+			return `{"success": true, "id": "fileId", "title": "Old title"}`
+		}
+		return `{"success": true, "id": "fileId", "title": "New title"}`
+	}
+	api := API{Key: testKey, Secret: testSecret}
+
+	file, err := api.WaitUntil(context.Background(), "fileId", func(file Response) bool {
+		return stringField(file, "title") == "New title"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stringField(file, "title"); got != "New title" {
+		t.Errorf("got title %q, want %q", got, "New title")
+	}
+	if len(server.requests) != 3 {
+		t.Errorf("got %d requests, want 3", len(server.requests))
+	}
+	if got := server.lastRequest(t).Path; got != "/v1/files/show/fileId" {
+		t.Errorf("got request to %s, want /v1/files/show/fileId", got)
+	}
+}
+
+func TestWaitUntilNotFound(t *testing.T) {
+	server := newFakeServer(t)
+	server.respond = func(req recordedRequest) string {
+		if len(server.requests) < 3 {
+			server.status = http.StatusNotFound
+			return `{"success": false, "error": {"message": "File not found"}}`
+		}
+		server.status = 0
+		return `{"success": true, "id": "fileId", "title": "New title"}`
+	}
+	api := API{Key: testKey, Secret: testSecret}
+
+	file, err := api.WaitUntil(context.Background(), "fileId", func(file Response) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stringField(file, "title"); got != "New title" {
+		t.Errorf("got title %q, want %q", got, "New title")
+	}
+	if len(server.requests) != 3 {
+		t.Errorf("got %d requests, want 3", len(server.requests))
+	}
+}
+
+func TestWaitUntilPermanentError(t *testing.T) {
+	server := newFakeServer(t)
+	server.status = http.StatusForbidden
+	server.response = `{"success": false, "error": {"message": "Forbidden"}}`
+	api := API{Key: testKey, Secret: testSecret}
+
+	_, err := api.WaitUntil(context.Background(), "fileId", func(file Response) bool { return true })
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("got error %v, want %v", err, ErrUnauthorized)
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("got %d requests, want 1", len(server.requests))
+	}
+}
+
+func TestWaitUntilDeadline(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "id": "fileId", "title": "Old title"}`
+	api := API{Key: testKey, Secret: testSecret}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*waitInitialInterval)
+	defer cancel()
+	file, err := api.WaitUntil(ctx, "fileId", func(file Response) bool { return false })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := stringField(file, "title"); got != "Old title" {
+		t.Errorf("got last title %q, want %q", got, "Old title")
+	}
+	if len(server.requests) < 2 {
+		t.Errorf("got %d requests, want at least 2", len(server.requests))
+	}
+}
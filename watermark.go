@@ -0,0 +1,113 @@
+package publitio
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// Watermark is an image that can be overlaid on files delivered by Publitio.
+type Watermark struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Position  string `json:"position"`
+	Padding   int    `json:"padding"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// WatermarkOptions are the settings of a watermark. Empty fields are left out, so they keep
+// their default when creating a watermark and their current value when updating one.
+type WatermarkOptions struct {
+	Name     string     // Name of the watermark, required when creating one
+	Position string     // Where the watermark is placed, for example "center" or "bottom-right"
+	Padding  *int       // Distance in pixels from the edges
+	Values   url.Values // Any other watermark parameters, passed along as they are
+}
+
+func (o WatermarkOptions) values() url.Values {
+	values := copyValues(o.Values)
+	if o.Name != "" {
+		values.Set("name", o.Name)
+	}
+	if o.Position != "" {
+		values.Set("position", o.Position)
+	}
+	if o.Padding != nil {
+		values.Set("padding", strconv.Itoa(*o.Padding))
+	}
+	return values
+}
+
+// WatermarkService provides typed access to the watermarks endpoints.
+type WatermarkService struct {
+	api *API
+}
+
+// Watermarks returns a WatermarkService that makes its calls through api.
+func (api *API) Watermarks() *WatermarkService {
+	return &WatermarkService{api: api}
+}
+
+// Create uploads image as a new watermark with the given settings and returns it.
+// The image is sent like a file passed to UploadFile, so NamedFile can name it.
+func (s *WatermarkService) Create(ctx context.Context, image io.Reader, watermark WatermarkOptions, opts ...CallOption) (*Watermark, error) {
+	res, err := s.api.uploadContext(ctx, "/watermarks/create", image, watermark.values(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWatermark(res)
+}
+
+// Show returns the watermark with the given ID.
+func (s *WatermarkService) Show(ctx context.Context, id string, opts ...CallOption) (*Watermark, error) {
+	res, err := s.api.CallContext(ctx, "GET", "watermarks/show/"+id, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWatermark(res)
+}
+
+// List returns all watermarks.
+func (s *WatermarkService) List(ctx context.Context, opts ...CallOption) ([]Watermark, error) {
+	res, err := s.api.CallContext(ctx, "GET", "watermarks/list", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Watermarks []Watermark `json:"watermarks"`
+	}
+	err = decodeResponse(res, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Watermarks, nil
+}
+
+// Update changes the non-empty settings of the watermark with the given ID and returns
+// the updated watermark. The image of a watermark can't be changed.
+func (s *WatermarkService) Update(ctx context.Context, id string, watermark WatermarkOptions, opts ...CallOption) (*Watermark, error) {
+	res, err := s.api.CallContext(ctx, "PUT", "watermarks/update/"+id, watermark.values(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWatermark(res)
+}
+
+// Delete deletes the watermark with the given ID.
+func (s *WatermarkService) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	_, err := s.api.CallContext(ctx, "DELETE", "watermarks/delete/"+id, nil, opts...)
+	return err
+}
+
+func decodeWatermark(res Response) (*Watermark, error) {
+	var watermark Watermark
+	err := decodeResponse(res, &watermark)
+	if err != nil {
+		return nil, err
+	}
+	return &watermark, nil
+}
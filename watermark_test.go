@@ -0,0 +1,74 @@
+package publitio
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWatermarkService(t *testing.T) {
+	server := newFakeServer(t)
+	server.response = `{"success": true, "id": "w1", "name": "logo", "position": "bottom-right", "padding": 10}`
+	server.responses = map[string]string{
+		"/v1/watermarks/list": `{"success": true, "watermarks": [{"id": "w1", "name": "logo"}]}`,
+	}
+	api := API{Key: testKey, Secret: testSecret}
+	watermarks := api.Watermarks()
+	ctx := context.Background()
+
+	padding := 10
+	image := NamedFile(strings.NewReader("png data"), "logo.png", "image/png")
+	watermark, err := watermarks.Create(ctx, image, WatermarkOptions{Name: "logo", Position: "bottom-right", Padding: &padding})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watermark.ID != "w1" || watermark.Padding != 10 {
+		t.Errorf("got %+v, want the created watermark", watermark)
+	}
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/watermarks/create" {
+		t.Errorf("got %s %s, want POST /v1/watermarks/create", req.Method, req.Path)
+	}
+	if req.Query.Get("name") != "logo" || req.Query.Get("position") != "bottom-right" || req.Query.Get("padding") != "10" {
+		t.Errorf("got query %v, want the watermark settings", req.Query)
+	}
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") || !bytes.Contains(req.Body, []byte("png data")) {
+		t.Errorf("got %s body %q, want the image as multipart form", req.Header.Get("Content-Type"), req.Body)
+	}
+
+	_, err = watermarks.Update(ctx, "w1", WatermarkOptions{Position: "center"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = server.lastRequest(t)
+	if req.Method != "PUT" || req.Path != "/v1/watermarks/update/w1" || req.Query.Get("position") != "center" || req.Query.Has("padding") {
+		t.Errorf("got %s %s?%v, want PUT /v1/watermarks/update/w1 with only the position", req.Method, req.Path, req.Query)
+	}
+
+	list, err := watermarks.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "logo" {
+		t.Errorf("got %+v, want one watermark", list)
+	}
+
+	err = watermarks.Delete(ctx, "w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := server.lastRequest(t); req.Method != "DELETE" || req.Path != "/v1/watermarks/delete/w1" {
+		t.Errorf("got %s %s, want DELETE /v1/watermarks/delete/w1", req.Method, req.Path)
+	}
+}
+
+func TestWatermarkReadOnly(t *testing.T) {
+	newFakeServer(t)
+	api := API{Key: testKey, Secret: testSecret, ReadOnly: true}
+
+	_, err := api.Watermarks().Create(context.Background(), strings.NewReader("png data"), WatermarkOptions{Name: "logo"})
+	if err != ErrReadOnly {
+		t.Errorf("got error %v, want ErrReadOnly", err)
+	}
+}